@@ -40,17 +40,50 @@ func GenerateLang(name string, subclasses [][]string) {
 func main() {
 	GenerateLang("Expr", [][]string{
 		{"BinaryExpr", "Left Expr", "Operator tokenizer.Token", "Right Expr"},
+		{"ConditionalExpr", "Condition Expr", "Question tokenizer.Token", "Then Expr", "Else Expr"},
+		{"LogicalExpr", "Left Expr", "Operator tokenizer.Token", "Right Expr"},
 		{"UnaryExpr", "Operator tokenizer.Token", "Right Expr"},
 		{"GroupingExpr", "Inside Expr"},
 		{"CallExpr", "Calle Expr", "Paren tokenizer.Token", "Arguments []Expr"},
 		{"LiteralExpr", "Value tokenizer.Token"},
+		{"InterpolatedStringExpr", "Parts []Expr"},
+		{"FunctionExpr", "Keyword tokenizer.Token", "Params []tokenizer.Token", "ParamTypes []TypeExpr", "Return TypeExpr", "Body []Stmt"},
+		{"GetExpr", "Object Expr", "Name tokenizer.Token"},
+		{"SetExpr", "Object Expr", "Name tokenizer.Token", "Operator tokenizer.Token", "Value Expr"},
+		{"ListExpr", "Bracket tokenizer.Token", "Elements []Expr"},
+		{"MapExpr", "Brace tokenizer.Token", "Keys []Expr", "Values []Expr"},
+		{"IndexExpr", "Object Expr", "Bracket tokenizer.Token", "Index Expr"},
+		{"IndexSetExpr", "Object Expr", "Bracket tokenizer.Token", "Index Expr", "Operator tokenizer.Token", "Value Expr"},
+		{"SliceExpr", "Object Expr", "Bracket tokenizer.Token", "Start Expr", "End Expr"},
+	})
+	GenerateLang("TypeExpr", [][]string{
+		{"NamedType", "Name tokenizer.Token", "Arguments []TypeExpr"},
+		{"FunctionType", "Keyword tokenizer.Token", "Params []TypeExpr", "Return TypeExpr"},
+	})
+	GenerateLang("Pattern", [][]string{
+		{"LiteralPattern", "Value Expr"},
+		{"WildcardPattern", "Underscore tokenizer.Token"},
+		{"BindingPattern", "Name tokenizer.Token"},
+		{"AlternativePattern", "Alternatives []Pattern"},
+		{"ListPattern", "Bracket tokenizer.Token", "Elements []Pattern"},
+		{"StructPattern", "Name tokenizer.Token", "Fields []tokenizer.Token", "Patterns []Pattern"},
+		{"EnumPattern", "Enum tokenizer.Token", "Variant tokenizer.Token", "Patterns []Pattern"},
 	})
 	GenerateLang("Stmt", [][]string{
 		{"ExpressionStmt", "Expression Expr"},
 		{"PrintStmt", "Expression Expr"},
 		{"BlockStmt", "Statements []Stmt"},
-		{"IfStmt", "Condition Expr", "Block Stmt"},
-		{"VarDeclStmt", "Name tokenizer.Token", "Expression Expr"},
-		{"ForStmt", "PreStatement Stmt", "Condition Expr", "PostStatement Expr", "Block Stmt"},
+		{"IfStmt", "Condition Expr", "Block Stmt", "Else Stmt"},
+		{"VarDeclStmt", "Keyword tokenizer.Token", "Name tokenizer.Token", "Type TypeExpr", "Expression Expr"},
+		{"ForStmt", "Label tokenizer.Token", "PreStatement Stmt", "Condition Expr", "PostStatement Expr", "Block Stmt"},
+		{"FuncDeclStmt", "Name tokenizer.Token", "TypeParams []tokenizer.Token", "Bounds [][]tokenizer.Token", "Params []tokenizer.Token", "ParamTypes []TypeExpr", "Return TypeExpr", "Body []Stmt"},
+		{"StructDeclStmt", "Name tokenizer.Token", "TypeParams []tokenizer.Token", "Bounds [][]tokenizer.Token", "Fields []tokenizer.Token", "FieldTypes []TypeExpr", "Traits []tokenizer.Token", "Methods []FuncDeclStmt"},
+		{"TraitDeclStmt", "Name tokenizer.Token", "Methods []FuncDeclStmt", "Defaults []FuncDeclStmt"},
+		{"EnumDeclStmt", "Name tokenizer.Token", "Variants []tokenizer.Token", "Fields [][]tokenizer.Token", "FieldTypes [][]TypeExpr"},
+		{"ReturnStmt", "Keyword tokenizer.Token", "Value Expr"},
+		{"WhileStmt", "Label tokenizer.Token", "Condition Expr", "Block Stmt"},
+		{"BreakStmt", "Keyword tokenizer.Token", "Label tokenizer.Token"},
+		{"ContinueStmt", "Keyword tokenizer.Token", "Label tokenizer.Token"},
+		{"MatchStmt", "Keyword tokenizer.Token", "Value Expr", "Patterns []Pattern", "Guards []Expr", "Bodies []Stmt"},
 	})
 }
@@ -7,16 +7,55 @@ import (
 	"github.com/WhoDoIt/GoCompiler/internal/interpreter"
 	"github.com/WhoDoIt/GoCompiler/internal/parser"
 	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+	"github.com/WhoDoIt/GoCompiler/internal/types"
 )
 
+// parseArgs splits the arguments into the flags and the source file, ok is false unless exactly one source follows the flags
+func parseArgs(args []string) (check bool, showTypes bool, source string, ok bool) {
+	for len(args) > 0 {
+		if args[0] == "check" && !check {
+			check = true
+		} else if args[0] == "--show-types" && !showTypes {
+			showTypes = true
+		} else {
+			break
+		}
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return check, showTypes, "", false
+	}
+	return check, showTypes, args[0], true
+}
+
+// typeErrors prints the errors of the checker, they only stop the program in check mode and are warnings otherwise
+func typeErrors(errs []error, check bool) bool {
+	for _, v := range errs {
+		if check {
+			fmt.Println(v.Error())
+		} else {
+			fmt.Println("WARNING: " + v.Error())
+		}
+	}
+	if check && errs != nil {
+		fmt.Println("got type errors")
+		return true
+	}
+	return false
+}
+
 func main() {
-	args := os.Args
-	if len(args) != 2 {
+	check, showTypes, sourceName, ok := parseArgs(os.Args[1:])
+	if !ok {
 		fmt.Println("Invalid number of arguments")
+		fmt.Println("Usage: main [check] [--show-types] <source>")
 		os.Exit(1)
 	}
-	sourceName := os.Args[1]
-	fmt.Println("Eval", sourceName)
+	if check {
+		fmt.Println("Check", sourceName)
+	} else {
+		fmt.Println("Eval", sourceName)
+	}
 
 	// resultName := os.Args[2]
 	// fmt.Println("Eval", sourceName, "to", resultName)
@@ -42,7 +81,19 @@ func main() {
 		fmt.Println(err.Error())
 		os.Exit(1)
 	}
-	interpreter.Evaluate(expr)
+	declarations, errs := types.Check(expr)
+	if showTypes {
+		for _, v := range declarations {
+			fmt.Println(v.String())
+		}
+	}
+	if typeErrors(errs, check) {
+		os.Exit(1)
+	}
+	if check {
+		return
+	}
+	interpreter.Evaluate(interpreter.Fold(expr))
 
 	// fmt.Println(interpreter.StringVisitor{}.Print(expr))
 	// fmt.Println(interpreter.NumberEvalVisitor{}.Calculate(expr))
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args      string
+		check     bool
+		showTypes bool
+		source    string
+		ok        bool
+	}{
+		{"a.rose", false, false, "a.rose", true},
+		{"check a.rose", true, false, "a.rose", true},
+		{"check --show-types a.rose", true, true, "a.rose", true},
+		{"--show-types check a.rose", true, true, "a.rose", true},
+		{"--show-types a.rose", false, true, "a.rose", true},
+		{"check check", true, false, "check", true},
+		{"", false, false, "", false},
+		{"check", true, false, "", false},
+		{"check --show-types", true, true, "", false},
+		{"a.rose b.rose", false, false, "", false},
+		{"check a.rose --show-types", true, false, "", false},
+	}
+	for _, test := range tests {
+		check, showTypes, source, ok := parseArgs(strings.Fields(test.args))
+		if check != test.check || showTypes != test.showTypes || source != test.source || ok != test.ok {
+			t.Errorf("%q: got %v %v %q %v", test.args, check, showTypes, source, ok)
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	errs := []error{errors.New("[cannot assign Int to x of type String] on line 1")}
+	if typeErrors(errs, false) {
+		t.Errorf("type errors stopped a program outside check mode")
+	}
+	if !typeErrors(errs, true) {
+		t.Errorf("type errors did not stop check mode")
+	}
+	if typeErrors(nil, true) {
+		t.Errorf("check mode stopped without type errors")
+	}
+}
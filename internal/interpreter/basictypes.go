@@ -1,6 +1,11 @@
 package interpreter
 
 import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
 	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
 )
 
@@ -10,6 +15,7 @@ type RoseType interface {
 	operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType
 	operatorUnary(operator tokenizer.TokenType) RoseType
 	operatorCall(args []RoseType) RoseType
+	toString() string
 }
 
 type RoseString struct {
@@ -20,14 +26,118 @@ type RoseInt struct {
 	value int
 }
 
+type RoseFloat struct {
+	value float64
+}
+
 type RoseBool struct {
 	value bool
 }
 
+type RoseNil struct {
+}
+
+type RoseFunction struct {
+	name    string
+	params  []tokenizer.Token
+	body    []syntaxtree.Stmt
+	closure *scope
+	interp  *intepreter
+}
+
+type RoseNativeFunction struct {
+	name  string
+	arity int
+	fn    func(args []RoseType) RoseType
+}
+
+type RoseList struct {
+	elements []RoseType
+}
+
+// RoseMap keeps entries in insertion order, index maps a key's hashKey to its position
+type RoseMap struct {
+	index  map[any]int
+	keys   []RoseType
+	values []RoseType
+}
+
+// RoseStruct is the value a struct declaration binds, the traits it declares are looked up in sc when a method is missing
+type RoseStruct struct {
+	name    string
+	fields  []string
+	methods map[string]RoseFunction
+	traits  []string
+	sc      *scope
+}
+
+type RoseInstance struct {
+	structType *RoseStruct
+	fields     map[string]RoseType
+}
+
+// RoseTrait is the value a trait declaration binds, its default methods are given to the structs declaring it
+type RoseTrait struct {
+	name     string
+	defaults map[string]RoseFunction
+}
+
+type RoseEnum struct {
+	name     string
+	variants []string
+	fields   map[string][]string
+}
+
+type RoseEnumValue struct {
+	enum    *RoseEnum
+	variant string
+	values  []RoseType
+}
+
 type RuntimeError struct {
 	value string
 }
 
+type roseIndexable interface {
+	operatorIndex(index RoseType) RoseType
+	operatorSetIndex(index RoseType, val RoseType) RoseType
+}
+
+type roseGettable interface {
+	get(name string) RoseType
+}
+
+// roseHashable is implemented by values usable as map keys, equal values have equal hash keys
+type roseHashable interface {
+	hashKey() any
+}
+
+type roseContainer interface {
+	operatorContains(val RoseType) RoseType
+}
+
+// roseSliceable is implemented by values supporting xs[a:b], omitted bounds are passed as RoseNil
+type roseSliceable interface {
+	operatorSlice(start RoseType, end RoseType) RoseType
+}
+
+func tryErrors(vals ...RoseType) RoseType {
+	for _, v := range vals {
+		if val, ok := v.(RuntimeError); ok {
+			return val
+		}
+	}
+	return nil
+}
+
+// repr is toString with strings quoted, used for values nested in other values
+func repr(val RoseType) string {
+	if str, ok := val.(RoseString); ok {
+		return strconv.Quote(str.value)
+	}
+	return val.toString()
+}
+
 func tryDifferentTypesError(a RoseType, b RoseType) RuntimeError {
 	if val, ok := a.(RuntimeError); ok {
 		return val
@@ -38,6 +148,39 @@ func tryDifferentTypesError(a RoseType, b RoseType) RuntimeError {
 	return RuntimeError{value: "unsupported operation of (" + a.getType() + " and " + b.getType() + ")"}
 }
 
+// isTruthy is what conditions accept: nil and false are falsy, every other value is truthy
+func isTruthy(val RoseType) bool {
+	switch val := val.(type) {
+	case RoseNil, RuntimeError:
+		return false
+	case RoseBool:
+		return val.value
+	}
+	return true
+}
+
+// equalValues compares values of any types, values of different types are never equal
+func equalValues(a RoseType, b RoseType) RoseType {
+	if _, ok := a.(RuntimeError); ok {
+		return a
+	}
+	if _, ok := b.(RuntimeError); ok {
+		return b
+	}
+	if a.getType() != b.getType() && !(isNumber(a) && isNumber(b)) {
+		return RoseBool{value: false}
+	}
+	return a.operatorBinary(tokenizer.EQUAL_EQUAL, b)
+}
+
+func isNumber(val RoseType) bool {
+	switch val.(type) {
+	case RoseInt, RoseFloat:
+		return true
+	}
+	return false
+}
+
 func (s RoseString) getType() string {
 	return "String"
 }
@@ -46,6 +189,10 @@ func (s RoseString) zeroValue() RoseType {
 	return RoseString{value: ""}
 }
 
+func (s RoseString) hashKey() any {
+	return s.value
+}
+
 func (s RoseString) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
 	if other.getType() != s.getType() {
 		return tryDifferentTypesError(s, other)
@@ -69,6 +216,10 @@ func (s RoseString) operatorCall(args []RoseType) RoseType {
 	return tryDifferentTypesError(s, s)
 }
 
+func (s RoseString) toString() string {
+	return s.value
+}
+
 func (s RoseInt) getType() string {
 	return "Int"
 }
@@ -77,7 +228,14 @@ func (s RoseInt) zeroValue() RoseType {
 	return RoseInt{value: 0}
 }
 
+func (s RoseInt) hashKey() any {
+	return s.value
+}
+
 func (s RoseInt) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if val, ok := other.(RoseFloat); ok {
+		return RoseFloat{value: float64(s.value)}.operatorBinary(operator, val)
+	}
 	if other.getType() != s.getType() {
 		return tryDifferentTypesError(s, other)
 	}
@@ -89,11 +247,41 @@ func (s RoseInt) operatorBinary(operator tokenizer.TokenType, other RoseType) Ro
 	case tokenizer.STAR:
 		return RoseInt{value: s.value * other.(RoseInt).value}
 	case tokenizer.SLASH:
+		if other.(RoseInt).value == 0 {
+			return RuntimeError{value: "integer division by zero"}
+		}
 		return RoseInt{value: s.value / other.(RoseInt).value}
+	case tokenizer.PERCENT:
+		if other.(RoseInt).value == 0 {
+			return RuntimeError{value: "integer modulo by zero"}
+		}
+		return RoseInt{value: s.value % other.(RoseInt).value}
+	case tokenizer.STAR_STAR:
+		if other.(RoseInt).value < 0 {
+			return RoseFloat{value: math.Pow(float64(s.value), float64(other.(RoseInt).value))}
+		}
+		result, base := 1, s.value
+		for exp := other.(RoseInt).value; exp > 0; exp >>= 1 {
+			if exp&1 == 1 {
+				result *= base
+			}
+			base *= base
+		}
+		return RoseInt{value: result}
+	case tokenizer.LESS_LESS, tokenizer.GREATER_GREATER:
+		if other.(RoseInt).value < 0 {
+			return RuntimeError{value: "negative shift count " + strconv.Itoa(other.(RoseInt).value)}
+		}
+		if operator == tokenizer.LESS_LESS {
+			return RoseInt{value: s.value << other.(RoseInt).value}
+		}
+		return RoseInt{value: s.value >> other.(RoseInt).value}
 	case tokenizer.PIPE:
-		return RoseInt{value: s.value & other.(RoseInt).value}
+		return RoseInt{value: s.value | other.(RoseInt).value}
 	case tokenizer.AMPERSAND:
-		return RoseInt{value: s.value + other.(RoseInt).value}
+		return RoseInt{value: s.value & other.(RoseInt).value}
+	case tokenizer.CARET:
+		return RoseInt{value: s.value ^ other.(RoseInt).value}
 	case tokenizer.EQUAL_EQUAL:
 		return RoseBool{value: s.value == other.(RoseInt).value}
 	case tokenizer.LESS:
@@ -106,6 +294,9 @@ func (s RoseInt) operatorUnary(operator tokenizer.TokenType) RoseType {
 	if operator == tokenizer.MINUS {
 		return RoseInt{value: -s.value}
 	}
+	if operator == tokenizer.TILDE {
+		return RoseInt{value: ^s.value}
+	}
 	return tryDifferentTypesError(s, s)
 }
 
@@ -113,6 +304,74 @@ func (s RoseInt) operatorCall(args []RoseType) RoseType {
 	return tryDifferentTypesError(s, s)
 }
 
+func (s RoseInt) toString() string {
+	return strconv.Itoa(s.value)
+}
+
+func (s RoseFloat) getType() string {
+	return "Float"
+}
+
+func (s RoseFloat) zeroValue() RoseType {
+	return RoseFloat{value: 0}
+}
+
+// hashKey of a whole float matches the Int it is equal to
+func (s RoseFloat) hashKey() any {
+	if s.value == math.Trunc(s.value) && math.Abs(s.value) < 1<<62 {
+		return int(s.value)
+	}
+	return s.value
+}
+
+func (s RoseFloat) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if val, ok := other.(RoseInt); ok {
+		other = RoseFloat{value: float64(val.value)}
+	}
+	if other.getType() != s.getType() {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.PLUS:
+		return RoseFloat{value: s.value + other.(RoseFloat).value}
+	case tokenizer.MINUS:
+		return RoseFloat{value: s.value - other.(RoseFloat).value}
+	case tokenizer.STAR:
+		return RoseFloat{value: s.value * other.(RoseFloat).value}
+	case tokenizer.SLASH:
+		return RoseFloat{value: s.value / other.(RoseFloat).value}
+	case tokenizer.PERCENT:
+		return RoseFloat{value: math.Mod(s.value, other.(RoseFloat).value)}
+	case tokenizer.STAR_STAR:
+		return RoseFloat{value: math.Pow(s.value, other.(RoseFloat).value)}
+	case tokenizer.EQUAL_EQUAL:
+		return RoseBool{value: s.value == other.(RoseFloat).value}
+	case tokenizer.LESS:
+		return RoseBool{value: s.value < other.(RoseFloat).value}
+	}
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseFloat) operatorUnary(operator tokenizer.TokenType) RoseType {
+	if operator == tokenizer.MINUS {
+		return RoseFloat{value: -s.value}
+	}
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseFloat) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+// toString keeps a fraction on whole numbers so floats never look like ints
+func (s RoseFloat) toString() string {
+	res := strconv.FormatFloat(s.value, 'g', -1, 64)
+	if !strings.ContainsAny(res, ".eIN") {
+		res += ".0"
+	}
+	return res
+}
+
 func (s RoseBool) getType() string {
 	return "Bool"
 }
@@ -121,7 +380,24 @@ func (s RoseBool) zeroValue() RoseType {
 	return RoseBool{value: false}
 }
 
+func (s RoseBool) hashKey() any {
+	return s.value
+}
+
 func (s RoseBool) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if other.getType() != s.getType() {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.EQUAL_EQUAL:
+		return RoseBool{value: s.value == other.(RoseBool).value}
+	case tokenizer.AMPERSAND:
+		return RoseBool{value: s.value && other.(RoseBool).value}
+	case tokenizer.PIPE:
+		return RoseBool{value: s.value || other.(RoseBool).value}
+	case tokenizer.CARET:
+		return RoseBool{value: s.value != other.(RoseBool).value}
+	}
 	return tryDifferentTypesError(s, s)
 }
 
@@ -136,6 +412,600 @@ func (s RoseBool) operatorCall(args []RoseType) RoseType {
 	return tryDifferentTypesError(s, s)
 }
 
+func (s RoseBool) toString() string {
+	return strconv.FormatBool(s.value)
+}
+
+func (s RoseNil) getType() string {
+	return "Nil"
+}
+
+func (s RoseNil) zeroValue() RoseType {
+	return s
+}
+
+func (s RoseNil) hashKey() any {
+	return s
+}
+
+func (s RoseNil) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if other.getType() != s.getType() {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.EQUAL_EQUAL:
+		return RoseBool{value: true}
+	}
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseNil) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseNil) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseNil) toString() string {
+	return "nil"
+}
+
+func (s RoseFunction) getType() string {
+	return "Function"
+}
+
+func (s RoseFunction) zeroValue() RoseType {
+	return s
+}
+
+func (s RoseFunction) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	return tryDifferentTypesError(s, other)
+}
+
+func (s RoseFunction) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseFunction) operatorCall(args []RoseType) RoseType {
+	if len(args) != len(s.params) {
+		return RuntimeError{value: s.toString() + " expected " + strconv.Itoa(len(s.params)) + " arguments but got " + strconv.Itoa(len(args))}
+	}
+	if err := tryErrors(args...); err != nil {
+		return err
+	}
+	return s.interp.call(s, args)
+}
+
+func (s RoseFunction) bind(instance *RoseInstance) RoseFunction {
+	s.closure = &scope{vars: map[string]RoseType{"self": instance}, parent: s.closure}
+	return s
+}
+
+func (s RoseFunction) toString() string {
+	if s.name == "" {
+		return "<fn>"
+	}
+	return "<fn " + s.name + ">"
+}
+
+func (s RoseNativeFunction) getType() string {
+	return "Function"
+}
+
+func (s RoseNativeFunction) zeroValue() RoseType {
+	return s
+}
+
+func (s RoseNativeFunction) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	return tryDifferentTypesError(s, other)
+}
+
+func (s RoseNativeFunction) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s RoseNativeFunction) operatorCall(args []RoseType) RoseType {
+	if len(args) != s.arity {
+		return RuntimeError{value: s.name + " expected " + strconv.Itoa(s.arity) + " arguments but got " + strconv.Itoa(len(args))}
+	}
+	for _, v := range args {
+		if val, ok := v.(RuntimeError); ok {
+			return val
+		}
+	}
+	return s.fn(args)
+}
+
+func (s RoseNativeFunction) toString() string {
+	return "<native fn " + s.name + ">"
+}
+
+func (s *RoseList) getType() string {
+	return "List"
+}
+
+func (s *RoseList) zeroValue() RoseType {
+	return &RoseList{}
+}
+
+func (s *RoseList) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if other.getType() != s.getType() {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.PLUS:
+		elements := make([]RoseType, 0, len(s.elements)+len(other.(*RoseList).elements))
+		elements = append(elements, s.elements...)
+		elements = append(elements, other.(*RoseList).elements...)
+		return &RoseList{elements: elements}
+	case tokenizer.EQUAL_EQUAL:
+		if len(s.elements) != len(other.(*RoseList).elements) {
+			return RoseBool{value: false}
+		}
+		for i, v := range s.elements {
+			if res := equalValues(v, other.(*RoseList).elements[i]); !isTruthy(res) {
+				return res
+			}
+		}
+		return RoseBool{value: true}
+	}
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseList) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseList) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+// position converts a possibly negative index into a position in [0, len], end allows len itself
+func (s *RoseList) position(index RoseType, end bool) (int, RoseType) {
+	val, ok := index.(RoseInt)
+	if !ok {
+		return 0, RuntimeError{value: "list index must be Int, got " + index.getType()}
+	}
+	pos := val.value
+	if pos < 0 {
+		pos += len(s.elements)
+	}
+	if pos < 0 || pos > len(s.elements) || pos == len(s.elements) && !end {
+		return 0, RuntimeError{value: "list index " + strconv.Itoa(val.value) + " out of range for length " + strconv.Itoa(len(s.elements))}
+	}
+	return pos, nil
+}
+
+func (s *RoseList) operatorIndex(index RoseType) RoseType {
+	pos, err := s.position(index, false)
+	if err != nil {
+		return err
+	}
+	return s.elements[pos]
+}
+
+func (s *RoseList) operatorSetIndex(index RoseType, val RoseType) RoseType {
+	pos, err := s.position(index, false)
+	if err != nil {
+		return err
+	}
+	s.elements[pos] = val
+	return val
+}
+
+func (s *RoseList) operatorSlice(start RoseType, end RoseType) RoseType {
+	from, to := 0, len(s.elements)
+	var err RoseType
+	if _, ok := start.(RoseNil); !ok {
+		if from, err = s.position(start, true); err != nil {
+			return err
+		}
+	}
+	if _, ok := end.(RoseNil); !ok {
+		if to, err = s.position(end, true); err != nil {
+			return err
+		}
+	}
+	if from > to {
+		return RuntimeError{value: "slice start " + strconv.Itoa(from) + " is after end " + strconv.Itoa(to)}
+	}
+	elements := make([]RoseType, to-from)
+	copy(elements, s.elements[from:to])
+	return &RoseList{elements: elements}
+}
+
+func (s *RoseList) operatorContains(val RoseType) RoseType {
+	for _, v := range s.elements {
+		if isTruthy(equalValues(v, val)) {
+			return RoseBool{value: true}
+		}
+	}
+	return RoseBool{value: false}
+}
+
+func (s *RoseList) toString() string {
+	result := "["
+	for i, v := range s.elements {
+		if i > 0 {
+			result += ", "
+		}
+		result += repr(v)
+	}
+	return result + "]"
+}
+
+func newRoseMap() *RoseMap {
+	return &RoseMap{index: make(map[any]int)}
+}
+
+func mapKey(key RoseType) (any, RoseType) {
+	if val, ok := key.(roseHashable); ok {
+		return val.hashKey(), nil
+	}
+	return nil, RuntimeError{value: "unhashable map key of type " + key.getType()}
+}
+
+func (s *RoseMap) getType() string {
+	return "Map"
+}
+
+func (s *RoseMap) zeroValue() RoseType {
+	return newRoseMap()
+}
+
+func (s *RoseMap) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if other.getType() != s.getType() {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.EQUAL_EQUAL:
+		if len(s.keys) != len(other.(*RoseMap).keys) {
+			return RoseBool{value: false}
+		}
+		for i, v := range s.keys {
+			val := other.(*RoseMap).operatorIndex(v)
+			if _, ok := val.(RuntimeError); ok {
+				return RoseBool{value: false}
+			}
+			if res := equalValues(s.values[i], val); !isTruthy(res) {
+				return res
+			}
+		}
+		return RoseBool{value: true}
+	}
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseMap) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseMap) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseMap) operatorIndex(index RoseType) RoseType {
+	key, err := mapKey(index)
+	if err != nil {
+		return err
+	}
+	if pos, ok := s.index[key]; ok {
+		return s.values[pos]
+	}
+	return RuntimeError{value: "key " + repr(index) + " not found in map"}
+}
+
+func (s *RoseMap) operatorSetIndex(index RoseType, val RoseType) RoseType {
+	key, err := mapKey(index)
+	if err != nil {
+		return err
+	}
+	if pos, ok := s.index[key]; ok {
+		s.values[pos] = val
+		return val
+	}
+	s.index[key] = len(s.keys)
+	s.keys = append(s.keys, index)
+	s.values = append(s.values, val)
+	return val
+}
+
+func (s *RoseMap) operatorContains(val RoseType) RoseType {
+	key, err := mapKey(val)
+	if err != nil {
+		return err
+	}
+	_, ok := s.index[key]
+	return RoseBool{value: ok}
+}
+
+func (s *RoseMap) delete(index RoseType) RoseType {
+	key, err := mapKey(index)
+	if err != nil {
+		return err
+	}
+	pos, ok := s.index[key]
+	if !ok {
+		return RoseBool{value: false}
+	}
+	delete(s.index, key)
+	s.keys = append(s.keys[:pos], s.keys[pos+1:]...)
+	s.values = append(s.values[:pos], s.values[pos+1:]...)
+	for i := pos; i < len(s.keys); i++ {
+		k, _ := mapKey(s.keys[i])
+		s.index[k] = i
+	}
+	return RoseBool{value: true}
+}
+
+func (s *RoseMap) toString() string {
+	result := "{"
+	for i, v := range s.keys {
+		if i > 0 {
+			result += ", "
+		}
+		result += repr(v) + ": " + repr(s.values[i])
+	}
+	return result + "}"
+}
+
+func (s *RoseStruct) getType() string {
+	return "Struct"
+}
+
+func (s *RoseStruct) zeroValue() RoseType {
+	return s
+}
+
+func (s *RoseStruct) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if val, ok := other.(*RoseStruct); ok && operator == tokenizer.EQUAL_EQUAL {
+		return RoseBool{value: s == val}
+	}
+	return tryDifferentTypesError(s, other)
+}
+
+func (s *RoseStruct) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseStruct) operatorCall(args []RoseType) RoseType {
+	if len(args) != len(s.fields) {
+		return RuntimeError{value: s.name + " expected " + strconv.Itoa(len(s.fields)) + " arguments but got " + strconv.Itoa(len(args))}
+	}
+	instance := &RoseInstance{structType: s, fields: make(map[string]RoseType)}
+	for i, v := range s.fields {
+		if val, ok := args[i].(RuntimeError); ok {
+			return val
+		}
+		instance.fields[v] = args[i]
+	}
+	return instance
+}
+
+func (s *RoseStruct) toString() string {
+	return "<struct " + s.name + ">"
+}
+
+// method finds a method of the struct, falling back to the defaults of the traits it declares
+func (s *RoseStruct) method(name string) (RoseFunction, bool) {
+	if method, ok := s.methods[name]; ok {
+		return method, true
+	}
+	for _, v := range s.traits {
+		if trait, ok := s.sc.GetValue(v).(*RoseTrait); ok {
+			if method, ok := trait.defaults[name]; ok {
+				return method, true
+			}
+		}
+	}
+	return RoseFunction{}, false
+}
+
+func (s *RoseInstance) getType() string {
+	return s.structType.name
+}
+
+func (s *RoseInstance) zeroValue() RoseType {
+	return s
+}
+
+// operatorBinary dispatches == to an eq method and < to a less method, without eq instances are only equal to themselves
+func (s *RoseInstance) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	val, ok := other.(*RoseInstance)
+	if !ok {
+		return tryDifferentTypesError(s, other)
+	}
+	switch operator {
+	case tokenizer.EQUAL_EQUAL:
+		if method, ok := s.structType.method("eq"); ok {
+			return truth(method.bind(s).operatorCall([]RoseType{val}))
+		}
+		return RoseBool{value: s == val}
+	case tokenizer.LESS:
+		if method, ok := s.structType.method("less"); ok {
+			return truth(method.bind(s).operatorCall([]RoseType{val}))
+		}
+	}
+	return tryDifferentTypesError(s, other)
+}
+
+func truth(val RoseType) RoseType {
+	if _, ok := val.(RuntimeError); ok {
+		return val
+	}
+	return RoseBool{value: isTruthy(val)}
+}
+
+func (s *RoseInstance) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseInstance) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseInstance) get(name string) RoseType {
+	if val, ok := s.fields[name]; ok {
+		return val
+	}
+	if method, ok := s.structType.method(name); ok {
+		return method.bind(s)
+	}
+	return RuntimeError{value: s.structType.name + " has no field " + name}
+}
+
+func (s *RoseInstance) set(name string, val RoseType) RoseType {
+	if _, ok := s.fields[name]; !ok {
+		return RuntimeError{value: s.structType.name + " has no field " + name}
+	}
+	s.fields[name] = val
+	return val
+}
+
+func (s *RoseInstance) toString() string {
+	if method, ok := s.structType.method("show"); ok && len(method.params) == 0 {
+		val := method.bind(s).operatorCall(nil)
+		if str, ok := val.(RoseString); ok {
+			return str.value
+		}
+		return val.toString()
+	}
+	result := s.structType.name + "{"
+	for i, v := range s.structType.fields {
+		if i > 0 {
+			result += ", "
+		}
+		result += v + ": " + repr(s.fields[v])
+	}
+	return result + "}"
+}
+
+func (s *RoseTrait) getType() string {
+	return "Trait"
+}
+
+func (s *RoseTrait) zeroValue() RoseType {
+	return s
+}
+
+func (s *RoseTrait) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if val, ok := other.(*RoseTrait); ok && operator == tokenizer.EQUAL_EQUAL {
+		return RoseBool{value: s == val}
+	}
+	return tryDifferentTypesError(s, other)
+}
+
+func (s *RoseTrait) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseTrait) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseTrait) toString() string {
+	return "<trait " + s.name + ">"
+}
+
+func (s *RoseEnum) getType() string {
+	return "Enum"
+}
+
+func (s *RoseEnum) zeroValue() RoseType {
+	return s
+}
+
+func (s *RoseEnum) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	if val, ok := other.(*RoseEnum); ok && operator == tokenizer.EQUAL_EQUAL {
+		return RoseBool{value: s == val}
+	}
+	return tryDifferentTypesError(s, other)
+}
+
+func (s *RoseEnum) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseEnum) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+// get returns a variant without fields as a value and a variant with fields as its constructor
+func (s *RoseEnum) get(name string) RoseType {
+	fields, ok := s.fields[name]
+	if !ok {
+		return RuntimeError{value: s.name + " has no variant " + name}
+	}
+	if len(fields) == 0 {
+		return &RoseEnumValue{enum: s, variant: name}
+	}
+	return RoseNativeFunction{name: s.name + "." + name, arity: len(fields), fn: func(args []RoseType) RoseType {
+		return &RoseEnumValue{enum: s, variant: name, values: args}
+	}}
+}
+
+func (s *RoseEnum) toString() string {
+	return "<enum " + s.name + ">"
+}
+
+func (s *RoseEnumValue) getType() string {
+	return s.enum.name
+}
+
+func (s *RoseEnumValue) zeroValue() RoseType {
+	return s
+}
+
+// operatorBinary compares variants by value, values of the same variant are equal when their fields are
+func (s *RoseEnumValue) operatorBinary(operator tokenizer.TokenType, other RoseType) RoseType {
+	val, ok := other.(*RoseEnumValue)
+	if !ok || operator != tokenizer.EQUAL_EQUAL {
+		return tryDifferentTypesError(s, other)
+	}
+	if s.enum != val.enum || s.variant != val.variant {
+		return RoseBool{value: false}
+	}
+	for i, v := range s.values {
+		if res := equalValues(v, val.values[i]); !isTruthy(res) {
+			return res
+		}
+	}
+	return RoseBool{value: true}
+}
+
+func (s *RoseEnumValue) operatorUnary(operator tokenizer.TokenType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseEnumValue) operatorCall(args []RoseType) RoseType {
+	return tryDifferentTypesError(s, s)
+}
+
+func (s *RoseEnumValue) get(name string) RoseType {
+	for i, v := range s.enum.fields[s.variant] {
+		if v == name {
+			return s.values[i]
+		}
+	}
+	return RuntimeError{value: s.enum.name + "." + s.variant + " has no field " + name}
+}
+
+func (s *RoseEnumValue) toString() string {
+	result := s.enum.name + "." + s.variant
+	if len(s.values) == 0 {
+		return result
+	}
+	result += "("
+	for i, v := range s.values {
+		if i > 0 {
+			result += ", "
+		}
+		result += repr(v)
+	}
+	return result + ")"
+}
+
 func (s RuntimeError) getType() string {
 	return "RuntimeError"
 }
@@ -154,3 +1024,7 @@ func (s RuntimeError) operatorUnary(operator tokenizer.TokenType) RoseType {
 func (s RuntimeError) operatorCall(args []RoseType) RoseType {
 	return s
 }
+
+func (s RuntimeError) toString() string {
+	return s.value
+}
@@ -0,0 +1,125 @@
+package interpreter
+
+import (
+	"strconv"
+	"strings"
+)
+
+func builtins() []RoseNativeFunction {
+	return []RoseNativeFunction{
+		{name: "int", arity: 1, fn: builtinInt},
+		{name: "float", arity: 1, fn: builtinFloat},
+		{name: "str", arity: 1, fn: builtinStr},
+		{name: "len", arity: 1, fn: builtinLen},
+		{name: "push", arity: 2, fn: builtinPush},
+		{name: "pop", arity: 1, fn: builtinPop},
+		{name: "delete", arity: 2, fn: builtinDelete},
+		{name: "keys", arity: 1, fn: builtinKeys},
+		{name: "values", arity: 1, fn: builtinValues},
+	}
+}
+
+// builtinInt truncates floats towards zero and parses strings
+func builtinInt(args []RoseType) RoseType {
+	switch val := args[0].(type) {
+	case RoseInt:
+		return val
+	case RoseFloat:
+		return RoseInt{value: int(val.value)}
+	case RoseBool:
+		if val.value {
+			return RoseInt{value: 1}
+		}
+		return RoseInt{value: 0}
+	case RoseString:
+		res, err := strconv.Atoi(strings.TrimSpace(val.value))
+		if err != nil {
+			return RuntimeError{value: "cannot convert \"" + val.value + "\" to Int"}
+		}
+		return RoseInt{value: res}
+	}
+	return RuntimeError{value: "cannot convert " + args[0].getType() + " to Int"}
+}
+
+func builtinFloat(args []RoseType) RoseType {
+	switch val := args[0].(type) {
+	case RoseInt:
+		return RoseFloat{value: float64(val.value)}
+	case RoseFloat:
+		return val
+	case RoseString:
+		res, err := strconv.ParseFloat(strings.TrimSpace(val.value), 64)
+		if err != nil {
+			return RuntimeError{value: "cannot convert \"" + val.value + "\" to Float"}
+		}
+		return RoseFloat{value: res}
+	}
+	return RuntimeError{value: "cannot convert " + args[0].getType() + " to Float"}
+}
+
+func builtinStr(args []RoseType) RoseType {
+	return RoseString{value: args[0].toString()}
+}
+
+func builtinLen(args []RoseType) RoseType {
+	switch val := args[0].(type) {
+	case *RoseList:
+		return RoseInt{value: len(val.elements)}
+	case RoseString:
+		return RoseInt{value: len(val.value)}
+	case *RoseMap:
+		return RoseInt{value: len(val.keys)}
+	}
+	return RuntimeError{value: "len of " + args[0].getType() + " is undefined"}
+}
+
+// builtinPush appends to the list in place
+func builtinPush(args []RoseType) RoseType {
+	list, ok := args[0].(*RoseList)
+	if !ok {
+		return RuntimeError{value: "push expects List, got " + args[0].getType()}
+	}
+	list.elements = append(list.elements, args[1])
+	return RoseNil{}
+}
+
+// builtinPop removes and returns the last element of the list
+func builtinPop(args []RoseType) RoseType {
+	list, ok := args[0].(*RoseList)
+	if !ok {
+		return RuntimeError{value: "pop expects List, got " + args[0].getType()}
+	}
+	if len(list.elements) == 0 {
+		return RuntimeError{value: "pop from empty list"}
+	}
+	last := list.elements[len(list.elements)-1]
+	list.elements = list.elements[:len(list.elements)-1]
+	return last
+}
+
+// builtinDelete removes a key from the map and reports whether it was present
+func builtinDelete(args []RoseType) RoseType {
+	dict, ok := args[0].(*RoseMap)
+	if !ok {
+		return RuntimeError{value: "delete expects Map, got " + args[0].getType()}
+	}
+	return dict.delete(args[1])
+}
+
+// builtinKeys lists the keys of the map in insertion order
+func builtinKeys(args []RoseType) RoseType {
+	dict, ok := args[0].(*RoseMap)
+	if !ok {
+		return RuntimeError{value: "keys expects Map, got " + args[0].getType()}
+	}
+	return &RoseList{elements: append([]RoseType{}, dict.keys...)}
+}
+
+// builtinValues lists the values of the map in insertion order
+func builtinValues(args []RoseType) RoseType {
+	dict, ok := args[0].(*RoseMap)
+	if !ok {
+		return RuntimeError{value: "values expects Map, got " + args[0].getType()}
+	}
+	return &RoseList{elements: append([]RoseType{}, dict.values...)}
+}
@@ -0,0 +1,431 @@
+package interpreter
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+// folder rewrites a program with its constant expressions replaced by their values, consts are replaced
+// where they are in scope and operations on literals are computed the way the interpreter would
+type folder struct {
+	// values of the consts in scope, anything else declared maps to nil
+	consts *scope
+	interp *intepreter
+}
+
+// Fold computes the constant expressions of a program before it runs, an expression failing with a
+// RuntimeError is left alone so that the error is reported when it runs
+func Fold(stmt []syntaxtree.Stmt) []syntaxtree.Stmt {
+	f := &folder{consts: newScope(nil), interp: &intepreter{sc: newScope(nil)}}
+	return f.block(stmt)
+}
+
+// block folds statements sharing the current scope, the names they declare hide outer consts for the whole
+// block since closures declared before them look them up when called
+func (f *folder) block(stmt []syntaxtree.Stmt) []syntaxtree.Stmt {
+	for _, v := range stmt {
+		switch v := v.(type) {
+		case syntaxtree.VarDeclStmt:
+			f.consts.vars[v.Name.Content] = nil
+		case syntaxtree.FuncDeclStmt:
+			f.consts.vars[v.Name.Content] = nil
+		case syntaxtree.StructDeclStmt:
+			f.consts.vars[v.Name.Content] = nil
+		case syntaxtree.TraitDeclStmt:
+			f.consts.vars[v.Name.Content] = nil
+		case syntaxtree.EnumDeclStmt:
+			f.consts.vars[v.Name.Content] = nil
+		}
+	}
+	var result []syntaxtree.Stmt
+	for _, v := range stmt {
+		result = append(result, f.stmt(v))
+	}
+	return result
+}
+
+// scoped folds with names declared in a new scope, as for the parameters of a fn or the bindings of a match arm
+func (f *folder) scoped(names []string, fold func()) {
+	prev := f.consts
+	f.consts = newScope(prev)
+	for _, v := range names {
+		f.consts.vars[v] = nil
+	}
+	fold()
+	f.consts = prev
+}
+
+func (f *folder) function(params []tokenizer.Token, body []syntaxtree.Stmt) []syntaxtree.Stmt {
+	var names []string
+	for _, v := range params {
+		names = append(names, v.Content)
+	}
+	f.scoped(names, func() { body = f.block(body) })
+	return body
+}
+
+func (f *folder) stmt(stmt syntaxtree.Stmt) syntaxtree.Stmt {
+	if stmt == nil {
+		return nil
+	}
+	return syntaxtree.AcceptStmt[any](f, stmt).(syntaxtree.Stmt)
+}
+
+func (f *folder) expr(expr syntaxtree.Expr) syntaxtree.Expr {
+	if expr == nil {
+		return nil
+	}
+	return syntaxtree.AcceptExpr[syntaxtree.Expr](f, expr)
+}
+
+func (f *folder) exprs(exprs []syntaxtree.Expr) []syntaxtree.Expr {
+	var result []syntaxtree.Expr
+	for _, v := range exprs {
+		result = append(result, f.expr(v))
+	}
+	return result
+}
+
+// constant returns the value of an expression that is a literal, negative numbers are a minus and a literal
+func (f *folder) constant(expr syntaxtree.Expr) (RoseType, bool) {
+	switch expr := expr.(type) {
+	case syntaxtree.LiteralExpr:
+		if expr.Value.Type == tokenizer.IDENTIFIER {
+			return nil, false
+		}
+	case syntaxtree.UnaryExpr:
+		if right, ok := expr.Right.(syntaxtree.LiteralExpr); !ok || right.Value.Type != tokenizer.NUMBER || expr.Operator.Type != tokenizer.MINUS {
+			return nil, false
+		}
+	default:
+		return nil, false
+	}
+	val := syntaxtree.AcceptExpr[RoseType](f.interp, expr)
+	if _, ok := val.(RuntimeError); ok {
+		return nil, false
+	}
+	return val, true
+}
+
+// fold evaluates expr when all of its operands are literals
+func (f *folder) fold(expr syntaxtree.Expr, operands ...syntaxtree.Expr) syntaxtree.Expr {
+	line := 0
+	for _, v := range operands {
+		if _, ok := f.constant(v); !ok {
+			return expr
+		}
+		line = literalLine(v)
+	}
+	val := syntaxtree.AcceptExpr[RoseType](f.interp, expr)
+	if result, ok := literal(val, line); ok {
+		return result
+	}
+	return expr
+}
+
+func literalLine(expr syntaxtree.Expr) int {
+	if unary, ok := expr.(syntaxtree.UnaryExpr); ok {
+		return unary.Operator.Line
+	}
+	return expr.(syntaxtree.LiteralExpr).Value.Line
+}
+
+// literal is the expression evaluating to val, only numbers, strings, bools and nil have one
+func literal(val RoseType, line int) (syntaxtree.Expr, bool) {
+	var token tokenizer.Token
+	switch val := val.(type) {
+	case RoseInt:
+		if val.value < 0 && val.value != math.MinInt {
+			return negative(RoseInt{value: -val.value}, line)
+		}
+		if val.value < 0 {
+			return nil, false
+		}
+		token = tokenizer.Token{Type: tokenizer.NUMBER, Content: strconv.Itoa(val.value)}
+	case RoseFloat:
+		if math.IsInf(val.value, 0) || math.IsNaN(val.value) {
+			return nil, false
+		}
+		if math.Signbit(val.value) {
+			return negative(RoseFloat{value: -val.value}, line)
+		}
+		token = tokenizer.Token{Type: tokenizer.NUMBER, Content: val.toString()}
+	case RoseString:
+		token = tokenizer.Token{Type: tokenizer.STRING, Content: val.value}
+	case RoseBool:
+		token = tokenizer.Token{Type: tokenizer.FALSE, Content: "false"}
+		if val.value {
+			token = tokenizer.Token{Type: tokenizer.TRUE, Content: "true"}
+		}
+	case RoseNil:
+		token = tokenizer.Token{Type: tokenizer.NIL, Content: "nil"}
+	default:
+		return nil, false
+	}
+	token.Len, token.Line = len(token.Content), line
+	return syntaxtree.LiteralExpr{Value: token}, true
+}
+
+func negative(val RoseType, line int) (syntaxtree.Expr, bool) {
+	right, ok := literal(val, line)
+	minus := tokenizer.Token{Type: tokenizer.MINUS, Content: "-", Len: 1, Line: line}
+	return syntaxtree.UnaryExpr{Operator: minus, Right: right}, ok
+}
+
+// bindings are the names a pattern declares
+func bindings(pattern syntaxtree.Pattern) []string {
+	var names []string
+	switch pattern := pattern.(type) {
+	case syntaxtree.BindingPattern:
+		names = append(names, pattern.Name.Content)
+	case syntaxtree.AlternativePattern:
+		for _, v := range pattern.Alternatives {
+			names = append(names, bindings(v)...)
+		}
+	case syntaxtree.ListPattern:
+		for _, v := range pattern.Elements {
+			names = append(names, bindings(v)...)
+		}
+	case syntaxtree.StructPattern:
+		for _, v := range pattern.Patterns {
+			names = append(names, bindings(v)...)
+		}
+	case syntaxtree.EnumPattern:
+		for _, v := range pattern.Patterns {
+			names = append(names, bindings(v)...)
+		}
+	}
+	return names
+}
+
+func (f *folder) VisitBinaryExpr(expr syntaxtree.BinaryExpr) syntaxtree.Expr {
+	expr.Right = f.expr(expr.Right)
+	if _, ok := compoundOperators[expr.Operator.Type]; ok || expr.Operator.Type == tokenizer.EQUAL {
+		return expr
+	}
+	expr.Left = f.expr(expr.Left)
+	return f.fold(expr, expr.Left, expr.Right)
+}
+
+// VisitConditionalExpr picks the branch when the condition is constant, whatever the branches are
+func (f *folder) VisitConditionalExpr(expr syntaxtree.ConditionalExpr) syntaxtree.Expr {
+	expr.Condition = f.expr(expr.Condition)
+	expr.Then = f.expr(expr.Then)
+	expr.Else = f.expr(expr.Else)
+	if cond, ok := f.constant(expr.Condition); ok {
+		if isTruthy(cond) {
+			return expr.Then
+		}
+		return expr.Else
+	}
+	return expr
+}
+
+func (f *folder) VisitLogicalExpr(expr syntaxtree.LogicalExpr) syntaxtree.Expr {
+	expr.Left = f.expr(expr.Left)
+	expr.Right = f.expr(expr.Right)
+	return f.fold(expr, expr.Left, expr.Right)
+}
+
+func (f *folder) VisitUnaryExpr(expr syntaxtree.UnaryExpr) syntaxtree.Expr {
+	expr.Right = f.expr(expr.Right)
+	return f.fold(expr, expr.Right)
+}
+
+func (f *folder) VisitGroupingExpr(expr syntaxtree.GroupingExpr) syntaxtree.Expr {
+	expr.Inside = f.expr(expr.Inside)
+	if _, ok := f.constant(expr.Inside); ok {
+		return expr.Inside
+	}
+	return expr
+}
+
+func (f *folder) VisitCallExpr(expr syntaxtree.CallExpr) syntaxtree.Expr {
+	expr.Calle = f.expr(expr.Calle)
+	expr.Arguments = f.exprs(expr.Arguments)
+	return expr
+}
+
+// VisitLiteralExpr replaces a const with its value
+func (f *folder) VisitLiteralExpr(expr syntaxtree.LiteralExpr) syntaxtree.Expr {
+	if expr.Value.Type != tokenizer.IDENTIFIER {
+		return expr
+	}
+	for sc := f.consts; sc != nil; sc = sc.parent {
+		if val, ok := sc.vars[expr.Value.Content]; ok {
+			if val == nil {
+				return expr
+			}
+			if result, ok := literal(val, expr.Value.Line); ok {
+				return result
+			}
+			return expr
+		}
+	}
+	return expr
+}
+
+func (f *folder) VisitInterpolatedStringExpr(expr syntaxtree.InterpolatedStringExpr) syntaxtree.Expr {
+	expr.Parts = f.exprs(expr.Parts)
+	return f.fold(expr, expr.Parts...)
+}
+
+func (f *folder) VisitFunctionExpr(expr syntaxtree.FunctionExpr) syntaxtree.Expr {
+	expr.Body = f.function(expr.Params, expr.Body)
+	return expr
+}
+
+func (f *folder) VisitGetExpr(expr syntaxtree.GetExpr) syntaxtree.Expr {
+	expr.Object = f.expr(expr.Object)
+	return expr
+}
+
+func (f *folder) VisitSetExpr(expr syntaxtree.SetExpr) syntaxtree.Expr {
+	expr.Object = f.expr(expr.Object)
+	expr.Value = f.expr(expr.Value)
+	return expr
+}
+
+func (f *folder) VisitListExpr(expr syntaxtree.ListExpr) syntaxtree.Expr {
+	expr.Elements = f.exprs(expr.Elements)
+	return expr
+}
+
+func (f *folder) VisitMapExpr(expr syntaxtree.MapExpr) syntaxtree.Expr {
+	expr.Keys = f.exprs(expr.Keys)
+	expr.Values = f.exprs(expr.Values)
+	return expr
+}
+
+func (f *folder) VisitIndexExpr(expr syntaxtree.IndexExpr) syntaxtree.Expr {
+	expr.Object = f.expr(expr.Object)
+	expr.Index = f.expr(expr.Index)
+	return expr
+}
+
+func (f *folder) VisitIndexSetExpr(expr syntaxtree.IndexSetExpr) syntaxtree.Expr {
+	expr.Object = f.expr(expr.Object)
+	expr.Index = f.expr(expr.Index)
+	expr.Value = f.expr(expr.Value)
+	return expr
+}
+
+func (f *folder) VisitSliceExpr(expr syntaxtree.SliceExpr) syntaxtree.Expr {
+	expr.Object = f.expr(expr.Object)
+	expr.Start = f.expr(expr.Start)
+	expr.End = f.expr(expr.End)
+	return expr
+}
+
+func (f *folder) VisitExpressionStmt(stmt syntaxtree.ExpressionStmt) any {
+	stmt.Expression = f.expr(stmt.Expression)
+	return stmt
+}
+
+func (f *folder) VisitPrintStmt(stmt syntaxtree.PrintStmt) any {
+	stmt.Expression = f.expr(stmt.Expression)
+	return stmt
+}
+
+func (f *folder) VisitBlockStmt(stmt syntaxtree.BlockStmt) any {
+	f.scoped(nil, func() { stmt.Statements = f.block(stmt.Statements) })
+	return stmt
+}
+
+func (f *folder) VisitIfStmt(stmt syntaxtree.IfStmt) any {
+	stmt.Condition = f.expr(stmt.Condition)
+	stmt.Block = f.stmt(stmt.Block)
+	stmt.Else = f.stmt(stmt.Else)
+	return stmt
+}
+
+// VisitVarDeclStmt makes a const with a constant value known to the rest of its scope
+func (f *folder) VisitVarDeclStmt(stmt syntaxtree.VarDeclStmt) any {
+	stmt.Expression = f.expr(stmt.Expression)
+	f.consts.vars[stmt.Name.Content] = nil
+	if val, ok := f.constant(stmt.Expression); ok && stmt.Keyword.Type == tokenizer.CONST {
+		f.consts.vars[stmt.Name.Content] = val
+	}
+	return stmt
+}
+
+func (f *folder) VisitForStmt(stmt syntaxtree.ForStmt) any {
+	f.scoped(nil, func() {
+		stmt.PreStatement = f.stmt(stmt.PreStatement)
+		stmt.Condition = f.expr(stmt.Condition)
+		stmt.PostStatement = f.expr(stmt.PostStatement)
+		stmt.Block = f.stmt(stmt.Block)
+	})
+	return stmt
+}
+
+func (f *folder) VisitFuncDeclStmt(stmt syntaxtree.FuncDeclStmt) any {
+	f.consts.vars[stmt.Name.Content] = nil
+	stmt.Body = f.function(stmt.Params, stmt.Body)
+	return stmt
+}
+
+func (f *folder) VisitStructDeclStmt(stmt syntaxtree.StructDeclStmt) any {
+	f.consts.vars[stmt.Name.Content] = nil
+	stmt.Methods = f.methods(stmt.Methods)
+	return stmt
+}
+
+func (f *folder) VisitTraitDeclStmt(stmt syntaxtree.TraitDeclStmt) any {
+	f.consts.vars[stmt.Name.Content] = nil
+	stmt.Defaults = f.methods(stmt.Defaults)
+	return stmt
+}
+
+// methods folds method bodies, self is declared between their closure and their parameters
+func (f *folder) methods(methods []syntaxtree.FuncDeclStmt) []syntaxtree.FuncDeclStmt {
+	var result []syntaxtree.FuncDeclStmt
+	f.scoped([]string{"self"}, func() {
+		for _, v := range methods {
+			v.Body = f.function(v.Params, v.Body)
+			result = append(result, v)
+		}
+	})
+	return result
+}
+
+func (f *folder) VisitEnumDeclStmt(stmt syntaxtree.EnumDeclStmt) any {
+	f.consts.vars[stmt.Name.Content] = nil
+	return stmt
+}
+
+func (f *folder) VisitReturnStmt(stmt syntaxtree.ReturnStmt) any {
+	stmt.Value = f.expr(stmt.Value)
+	return stmt
+}
+
+func (f *folder) VisitWhileStmt(stmt syntaxtree.WhileStmt) any {
+	stmt.Condition = f.expr(stmt.Condition)
+	stmt.Block = f.stmt(stmt.Block)
+	return stmt
+}
+
+func (f *folder) VisitBreakStmt(stmt syntaxtree.BreakStmt) any {
+	return stmt
+}
+
+func (f *folder) VisitContinueStmt(stmt syntaxtree.ContinueStmt) any {
+	return stmt
+}
+
+func (f *folder) VisitMatchStmt(stmt syntaxtree.MatchStmt) any {
+	stmt.Value = f.expr(stmt.Value)
+	guards := make([]syntaxtree.Expr, len(stmt.Guards))
+	bodies := make([]syntaxtree.Stmt, len(stmt.Bodies))
+	for i, v := range stmt.Patterns {
+		f.scoped(bindings(v), func() {
+			guards[i] = f.expr(stmt.Guards[i])
+			bodies[i] = f.stmt(stmt.Bodies[i])
+		})
+	}
+	stmt.Guards, stmt.Bodies = guards, bodies
+	return stmt
+}
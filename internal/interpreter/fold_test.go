@@ -0,0 +1,78 @@
+package interpreter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+)
+
+// TestFold checks what the expression of the last print of a program folds to
+func TestFold(t *testing.T) {
+	tests := []struct {
+		source string
+		folded string
+	}{
+		{"print 1 + 2 * 3;", "7"},
+		{"print (1 + 2) * 3;", "9"},
+		{"print 7 / 2.0;", "3.5"},
+		{"print 2 - 5;", "(- 3)"},
+		{"print -2.5 * 2;", "(- 5.0)"},
+		{"print \"a\" + \"b\";", "ab"},
+		{"print \"n${1 + 1}\";", "n2"},
+		{"print !true or false;", "false"},
+		{"print 1 < 2 ? \"yes\" : x;", "yes"},
+		{"print 1 / 0;", "(/ 1 0)"},
+		{"print 1e300 * 1e300;", "(* 1e300 1e300)"},
+		{"print x + 1 * 2;", "(+ x 2)"},
+		{"const N = 4; print N * N;", "16"},
+		{"const N = 4; const M = N + 1; print M;", "5"},
+		{"let N = 4; print N * 2;", "(* N 2)"},
+		{"const N = 4; { var N = 1; print N; }", "N"},
+		{"const N = 4; fn f() { return N; } { print N; var N = 1; }", "N"},
+		{"const N = 4; fn f(N) { print N; }", "N"},
+		{"const N = 4; match (1) { N => print N }", "N"},
+	}
+	for _, test := range tests {
+		tree := Fold(parse(t, test.source))
+		var last syntaxtree.Expr
+		var find func(stmt []syntaxtree.Stmt)
+		find = func(stmt []syntaxtree.Stmt) {
+			for _, v := range stmt {
+				switch v := v.(type) {
+				case syntaxtree.PrintStmt:
+					last = v.Expression
+				case syntaxtree.BlockStmt:
+					find(v.Statements)
+				case syntaxtree.FuncDeclStmt:
+					find(v.Body)
+				case syntaxtree.MatchStmt:
+					find(v.Bodies)
+				}
+			}
+		}
+		find(tree)
+		if got := (StringVisitor{}).Print(last); got != test.folded {
+			t.Errorf("%s: got %s, want %s", test.source, got, test.folded)
+		}
+	}
+}
+
+// TestFoldKeepsOutput checks that folding does not change what a program prints
+func TestFoldKeepsOutput(t *testing.T) {
+	sources := []string{
+		"print 9223372036854775807 + 1; print 2 ** 62 * 4; print -(-9223372036854775807 - 1);",
+		"print 0.1 + 0.2; print 1.0 / 3; print 1e21 * 10; print -0.0; print 2.5e-7 * 2;",
+		"print 5 % -3; print ~5; print 1 << 62 << 2; print 0x10 + 0b11; print 1_000 * 2;",
+		"print nil or 3; print 3 and \"x\"; print 1 == 1.0; print \"a\" < \"b\"; print -2 ** 2;",
+		"const A = 3; const B = A * A - 10; print B; print \"${A}:${B}\"; print A > B ? A : B;",
+		"print 1 / 0; print \"a\" - 1;",
+	}
+	for _, source := range sources {
+		plain := run(t, parse(t, source))
+		folded := run(t, Fold(parse(t, source)))
+		if strings.Join(plain, "\n") != strings.Join(folded, "\n") {
+			t.Errorf("%s:\nplain  %q\nfolded %q", source, plain, folded)
+		}
+	}
+}
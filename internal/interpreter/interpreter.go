@@ -2,15 +2,22 @@ package interpreter
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
 
 	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
 	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
 )
 
 type scope struct {
-	vars   map[string]RoseType
-	parent *scope
+	vars map[string]RoseType
+	// names declared with let or const, allocated by the first of them
+	immutable map[string]bool
+	parent    *scope
+}
+
+// newScope allocates a scope on the heap so closures created inside it keep sharing its variables
+func newScope(parent *scope) *scope {
+	return &scope{vars: make(map[string]RoseType), parent: parent}
 }
 
 func (s *scope) GetValue(name string) RoseType {
@@ -20,40 +27,90 @@ func (s *scope) GetValue(name string) RoseType {
 		if s.parent != nil {
 			return s.parent.GetValue(name)
 		} else {
-			return nil
+			return RuntimeError{value: "undefined variable " + name}
 		}
 	}
 }
 
-func (s *scope) AssignValue(name string, val RoseType) {
+// AssignValue changes the variable name refers to, returning a RuntimeError when there is none or it is immutable
+func (s *scope) AssignValue(name string, val RoseType) RoseType {
 	if _, ok := s.vars[name]; ok {
+		if s.immutable[name] {
+			return RuntimeError{value: "cannot assign to immutable " + name}
+		}
 		s.vars[name] = val
+		return nil
 	} else {
 		if s.parent != nil {
-			s.parent.AssignValue(name, val)
+			return s.parent.AssignValue(name, val)
 		} else {
-			return
+			return RuntimeError{value: "undefined variable " + name}
 		}
 	}
 }
 
 func (s *scope) DeclareValue(name string, val RoseType) {
 	s.vars[name] = val
+	delete(s.immutable, name)
+}
+
+func (s *scope) DeclareImmutable(name string, val RoseType) {
+	if s.immutable == nil {
+		s.immutable = make(map[string]bool)
+	}
+	s.vars[name] = val
+	s.immutable[name] = true
+}
+
+// returnSignal is passed up from statement visitors until the enclosing call consumes it
+type returnSignal struct {
+	value RoseType
+}
+
+type breakSignal struct {
+	label string
+}
+
+type continueSignal struct {
+	label string
 }
 
 type intepreter struct {
-	sc scope
+	sc *scope
 }
 
 func Evaluate(stmt []syntaxtree.Stmt) {
-	program := intepreter{sc: scope{vars: make(map[string]RoseType), parent: nil}}
+	program := intepreter{sc: newScope(nil)}
+	for _, v := range builtins() {
+		program.sc.DeclareValue(v.name, v)
+	}
+	program.declare(stmt)
+	for _, v := range stmt {
+		if !hoisted(v) {
+			program.eval(v)
+		}
+	}
+}
+
+// declare evaluates the traits, structs, enums and fns of a block before the rest of it, as the checker expects
+func (s *intepreter) declare(stmt []syntaxtree.Stmt) {
 	for _, v := range stmt {
-		program.eval(v)
+		if hoisted(v) {
+			s.eval(v)
+		}
 	}
 }
 
-func (s *intepreter) eval(stmt syntaxtree.Stmt) {
-	syntaxtree.AcceptStmt(s, stmt)
+func hoisted(stmt syntaxtree.Stmt) bool {
+	switch stmt.(type) {
+	case syntaxtree.TraitDeclStmt, syntaxtree.StructDeclStmt, syntaxtree.EnumDeclStmt, syntaxtree.FuncDeclStmt:
+		return true
+	}
+	return false
+}
+
+func (s *intepreter) eval(stmt syntaxtree.Stmt) any {
+	return syntaxtree.AcceptStmt(s, stmt)
 }
 
 func (s *intepreter) number(expr syntaxtree.Expr) RoseType {
@@ -64,43 +121,102 @@ func (s *intepreter) number(expr syntaxtree.Expr) RoseType {
 	return res
 }
 
+var compoundOperators = map[tokenizer.TokenType]tokenizer.TokenType{
+	tokenizer.PLUS_EQUAL:    tokenizer.PLUS,
+	tokenizer.MINUS_EQUAL:   tokenizer.MINUS,
+	tokenizer.STAR_EQUAL:    tokenizer.STAR,
+	tokenizer.SLASH_EQUAL:   tokenizer.SLASH,
+	tokenizer.PERCENT_EQUAL: tokenizer.PERCENT,
+	tokenizer.PLUS_PLUS:     tokenizer.PLUS,
+	tokenizer.MINUS_MINUS:   tokenizer.MINUS,
+}
+
+// compound applies the operator of a compound assignment to the current value, plain = keeps val
+func compound(operator tokenizer.Token, current func() RoseType, val RoseType) RoseType {
+	if op, ok := compoundOperators[operator.Type]; ok {
+		return current().operatorBinary(op, val)
+	}
+	return val
+}
+
 func (s *intepreter) VisitBinaryExpr(expr syntaxtree.BinaryExpr) RoseType {
+	if _, ok := compoundOperators[expr.Operator.Type]; ok {
+		return s.assign(expr)
+	}
 	switch expr.Operator.Type {
+	case tokenizer.EQUAL_EQUAL:
+		return equalValues(s.number(expr.Left), s.number(expr.Right))
+	case tokenizer.EXCLAMATION_EQUAL:
+		return equalValues(s.number(expr.Left), s.number(expr.Right)).operatorUnary(tokenizer.EXCLAMATION)
 	case tokenizer.LESS_EQUAL:
 		left := s.number(expr.Left)
-		right := s.number(expr.Right)
-		if val, ok := left.operatorBinary(tokenizer.LESS, right).(RuntimeError); ok {
-			return val
-		}
-		if val, ok := left.operatorBinary(tokenizer.EQUAL_EQUAL, right).(RuntimeError); ok {
-			return val
-		}
-		result := RoseBool{value: left.operatorBinary(tokenizer.EQUAL_EQUAL, right).(RoseBool).value || left.operatorBinary(tokenizer.LESS_EQUAL, right).(RoseBool).value}
-		return result
+		return s.number(expr.Right).operatorBinary(tokenizer.LESS, left).operatorUnary(tokenizer.EXCLAMATION)
 	case tokenizer.GREATER:
+		left := s.number(expr.Left)
+		return s.number(expr.Right).operatorBinary(tokenizer.LESS, left)
+	case tokenizer.GREATER_EQUAL:
+		return s.number(expr.Left).operatorBinary(tokenizer.LESS, s.number(expr.Right)).operatorUnary(tokenizer.EXCLAMATION)
+	case tokenizer.IN:
 		left := s.number(expr.Left)
 		right := s.number(expr.Right)
-		if val, ok := left.operatorBinary(tokenizer.LESS, right).(RuntimeError); ok {
-			return val
+		if err := tryErrors(left, right); err != nil {
+			return err
 		}
-		if val, ok := left.operatorBinary(tokenizer.EQUAL_EQUAL, right).(RuntimeError); ok {
-			return val
+		if val, ok := right.(roseContainer); ok {
+			return val.operatorContains(left)
 		}
-		result := RoseBool{value: left.operatorBinary(tokenizer.EQUAL_EQUAL, right).(RoseBool).value || left.operatorBinary(tokenizer.LESS_EQUAL, right).(RoseBool).value}
-		return result.operatorUnary(tokenizer.EXCLAMATION)
-	case tokenizer.GREATER_EQUAL:
-		return s.number(expr.Left).operatorBinary(tokenizer.LESS, s.number(expr.Right)).operatorUnary(tokenizer.EXCLAMATION)
+		return RuntimeError{value: "cannot check membership in " + right.getType()}
 	case tokenizer.EQUAL:
-		val := s.number(expr.Right)
-		s.sc.AssignValue(expr.Left.(syntaxtree.LiteralExpr).Value.Content, val)
-		return val
+		return s.assign(expr)
 	default:
 		return s.number(expr.Left).operatorBinary(expr.Operator.Type, s.number(expr.Right))
 	}
 }
 
+func (s *intepreter) assign(expr syntaxtree.BinaryExpr) RoseType {
+	name := expr.Left.(syntaxtree.LiteralExpr).Value.Content
+	val := s.number(expr.Right)
+	if _, ok := val.(RuntimeError); ok {
+		return val
+	}
+	val = compound(expr.Operator, func() RoseType { return s.sc.GetValue(name) }, val)
+	if _, ok := val.(RuntimeError); ok {
+		return val
+	}
+	if err := s.sc.AssignValue(name, val); err != nil {
+		return err
+	}
+	return val
+}
+
+func (s *intepreter) VisitConditionalExpr(expr syntaxtree.ConditionalExpr) RoseType {
+	cond := s.number(expr.Condition)
+	if _, ok := cond.(RuntimeError); ok {
+		return cond
+	}
+	if isTruthy(cond) {
+		return s.number(expr.Then)
+	}
+	return s.number(expr.Else)
+}
+
+func (s *intepreter) VisitLogicalExpr(expr syntaxtree.LogicalExpr) RoseType {
+	left := s.number(expr.Left)
+	if _, ok := left.(RuntimeError); ok {
+		return left
+	}
+	if expr.Operator.Type == tokenizer.OR && isTruthy(left) || expr.Operator.Type == tokenizer.AND && !isTruthy(left) {
+		return left
+	}
+	return s.number(expr.Right)
+}
+
 func (s *intepreter) VisitUnaryExpr(expr syntaxtree.UnaryExpr) RoseType {
-	return s.number(expr.Right).operatorUnary(expr.Operator.Type)
+	right := s.number(expr.Right)
+	if _, ok := right.(RuntimeError); !ok && expr.Operator.Type == tokenizer.EXCLAMATION {
+		return RoseBool{value: !isTruthy(right)}
+	}
+	return right.operatorUnary(expr.Operator.Type)
 }
 
 func (s *intepreter) VisitGroupingExpr(expr syntaxtree.GroupingExpr) RoseType {
@@ -111,17 +227,178 @@ func (s *intepreter) VisitLiteralExpr(expr syntaxtree.LiteralExpr) RoseType {
 	if expr.Value.Type == tokenizer.IDENTIFIER {
 		return s.sc.GetValue(expr.Value.Content)
 	}
+	switch expr.Value.Type {
+	case tokenizer.TRUE:
+		return RoseBool{value: true}
+	case tokenizer.FALSE:
+		return RoseBool{value: false}
+	case tokenizer.NIL:
+		return RoseNil{}
+	}
 	if expr.Value.Type == tokenizer.STRING {
 		return RoseString{value: expr.Value.Content}
+	} else if tokenizer.IsFloat(expr.Value.Content) {
+		res, err := tokenizer.ParseFloat(expr.Value.Content)
+		if err != nil {
+			return RuntimeError{value: "bad float literal " + expr.Value.Content}
+		}
+		return RoseFloat{value: res}
 	} else {
-		res, _ := strconv.Atoi(expr.Value.Content)
-		return RoseInt{value: int(res)}
+		res, err := tokenizer.ParseInt(expr.Value.Content)
+		if err != nil {
+			return RuntimeError{value: "bad int literal " + expr.Value.Content}
+		}
+		return RoseInt{value: res}
 	}
 }
 
+func (s *intepreter) VisitGetExpr(expr syntaxtree.GetExpr) RoseType {
+	object := s.number(expr.Object)
+	if gettable, ok := object.(roseGettable); ok {
+		return gettable.get(expr.Name.Content)
+	}
+	if val, ok := object.(RuntimeError); ok {
+		return val
+	}
+	return RuntimeError{value: "cannot get field " + expr.Name.Content + " of " + object.getType()}
+}
+
+func (s *intepreter) VisitSetExpr(expr syntaxtree.SetExpr) RoseType {
+	object := s.number(expr.Object)
+	if val, ok := object.(RuntimeError); ok {
+		return val
+	}
+	instance, ok := object.(*RoseInstance)
+	if !ok {
+		return RuntimeError{value: "cannot set field " + expr.Name.Content + " of " + object.getType()}
+	}
+	val := s.number(expr.Value)
+	if _, ok := val.(RuntimeError); ok {
+		return val
+	}
+	val = compound(expr.Operator, func() RoseType { return instance.get(expr.Name.Content) }, val)
+	if _, ok := val.(RuntimeError); ok {
+		return val
+	}
+	return instance.set(expr.Name.Content, val)
+}
+
+func (s *intepreter) VisitListExpr(expr syntaxtree.ListExpr) RoseType {
+	list := &RoseList{elements: make([]RoseType, 0, len(expr.Elements))}
+	for _, v := range expr.Elements {
+		val := s.number(v)
+		if _, ok := val.(RuntimeError); ok {
+			return val
+		}
+		list.elements = append(list.elements, val)
+	}
+	return list
+}
+
+func (s *intepreter) VisitMapExpr(expr syntaxtree.MapExpr) RoseType {
+	dict := newRoseMap()
+	for i := range expr.Keys {
+		key := s.number(expr.Keys[i])
+		value := s.number(expr.Values[i])
+		if err := tryErrors(key, value); err != nil {
+			return err
+		}
+		if res, ok := dict.operatorSetIndex(key, value).(RuntimeError); ok {
+			return res
+		}
+	}
+	return dict
+}
+
+func (s *intepreter) VisitIndexExpr(expr syntaxtree.IndexExpr) RoseType {
+	object := s.number(expr.Object)
+	index := s.number(expr.Index)
+	if err := tryErrors(object, index); err != nil {
+		return err
+	}
+	if val, ok := object.(roseIndexable); ok {
+		return val.operatorIndex(index)
+	}
+	return RuntimeError{value: "cannot index " + object.getType()}
+}
+
+func (s *intepreter) VisitIndexSetExpr(expr syntaxtree.IndexSetExpr) RoseType {
+	object := s.number(expr.Object)
+	index := s.number(expr.Index)
+	value := s.number(expr.Value)
+	if err := tryErrors(object, index, value); err != nil {
+		return err
+	}
+	if val, ok := object.(roseIndexable); ok {
+		value = compound(expr.Operator, func() RoseType { return val.operatorIndex(index) }, value)
+		if _, ok := value.(RuntimeError); ok {
+			return value
+		}
+		return val.operatorSetIndex(index, value)
+	}
+	return RuntimeError{value: "cannot index " + object.getType()}
+}
+
+func (s *intepreter) VisitSliceExpr(expr syntaxtree.SliceExpr) RoseType {
+	object := s.number(expr.Object)
+	var start, end RoseType = RoseNil{}, RoseNil{}
+	if expr.Start != nil {
+		start = s.number(expr.Start)
+	}
+	if expr.End != nil {
+		end = s.number(expr.End)
+	}
+	if err := tryErrors(object, start, end); err != nil {
+		return err
+	}
+	if val, ok := object.(roseSliceable); ok {
+		return val.operatorSlice(start, end)
+	}
+	return RuntimeError{value: "cannot slice " + object.getType()}
+}
+
+func (s *intepreter) VisitInterpolatedStringExpr(expr syntaxtree.InterpolatedStringExpr) RoseType {
+	var result strings.Builder
+	for _, v := range expr.Parts {
+		val := s.number(v)
+		if _, ok := val.(RuntimeError); ok {
+			return val
+		}
+		result.WriteString(val.toString())
+	}
+	return RoseString{value: result.String()}
+}
+
 func (s *intepreter) VisitCallExpr(expr syntaxtree.CallExpr) RoseType {
-	panic("BROTHER")
-	return nil
+	callee := s.number(expr.Calle)
+	var args []RoseType
+	for _, v := range expr.Arguments {
+		args = append(args, s.number(v))
+	}
+	return callee.operatorCall(args)
+}
+
+func (s *intepreter) VisitFunctionExpr(expr syntaxtree.FunctionExpr) RoseType {
+	return RoseFunction{params: expr.Params, body: expr.Body, closure: s.sc, interp: s}
+}
+
+func (s *intepreter) call(fn RoseFunction, args []RoseType) RoseType {
+	prev := s.sc
+	s.sc = newScope(fn.closure)
+	for i, v := range fn.params {
+		s.sc.DeclareValue(v.Content, args[i])
+	}
+	defer func() { s.sc = prev }()
+	s.declare(fn.body)
+	for _, v := range fn.body {
+		if hoisted(v) {
+			continue
+		}
+		if signal, ok := s.eval(v).(returnSignal); ok {
+			return signal.value
+		}
+	}
+	return RoseNil{}
 }
 
 func (s *intepreter) VisitExpressionStmt(stmt syntaxtree.ExpressionStmt) any {
@@ -130,38 +407,167 @@ func (s *intepreter) VisitExpressionStmt(stmt syntaxtree.ExpressionStmt) any {
 }
 func (s *intepreter) VisitPrintStmt(stmt syntaxtree.PrintStmt) any {
 	value := s.number(stmt.Expression)
-	fmt.Println("> ", value)
+	fmt.Println("> ", value.toString())
 	return nil
 }
 func (s *intepreter) VisitVarDeclStmt(stmt syntaxtree.VarDeclStmt) any {
 	value := s.number(stmt.Expression)
-	s.sc.DeclareValue(stmt.Name.Content, value)
+	if stmt.Keyword.Type == tokenizer.VAR {
+		s.sc.DeclareValue(stmt.Name.Content, value)
+	} else {
+		s.sc.DeclareImmutable(stmt.Name.Content, value)
+	}
+	return nil
+}
+
+func (s *intepreter) VisitFuncDeclStmt(stmt syntaxtree.FuncDeclStmt) any {
+	s.sc.DeclareValue(stmt.Name.Content, RoseFunction{name: stmt.Name.Content, params: stmt.Params, body: stmt.Body, closure: s.sc, interp: s})
+	return nil
+}
+
+func (s *intepreter) VisitStructDeclStmt(stmt syntaxtree.StructDeclStmt) any {
+	structType := &RoseStruct{name: stmt.Name.Content, methods: make(map[string]RoseFunction), sc: s.sc}
+	for _, v := range stmt.Fields {
+		structType.fields = append(structType.fields, v.Content)
+	}
+	for _, v := range stmt.Methods {
+		structType.methods[v.Name.Content] = RoseFunction{name: v.Name.Content, params: v.Params, body: v.Body, closure: s.sc, interp: s}
+	}
+	for _, v := range stmt.Traits {
+		structType.traits = append(structType.traits, v.Content)
+	}
+	s.sc.DeclareValue(stmt.Name.Content, structType)
+	return nil
+}
+func (s *intepreter) VisitTraitDeclStmt(stmt syntaxtree.TraitDeclStmt) any {
+	trait := &RoseTrait{name: stmt.Name.Content, defaults: make(map[string]RoseFunction)}
+	for _, v := range stmt.Defaults {
+		trait.defaults[v.Name.Content] = RoseFunction{name: v.Name.Content, params: v.Params, body: v.Body, closure: s.sc, interp: s}
+	}
+	s.sc.DeclareValue(stmt.Name.Content, trait)
 	return nil
 }
 
+func (s *intepreter) VisitEnumDeclStmt(stmt syntaxtree.EnumDeclStmt) any {
+	enum := &RoseEnum{name: stmt.Name.Content, fields: make(map[string][]string)}
+	for i, v := range stmt.Variants {
+		enum.variants = append(enum.variants, v.Content)
+		fields := []string{}
+		for _, field := range stmt.Fields[i] {
+			fields = append(fields, field.Content)
+		}
+		enum.fields[v.Content] = fields
+	}
+	s.sc.DeclareValue(stmt.Name.Content, enum)
+	return nil
+}
+
+func (s *intepreter) VisitReturnStmt(stmt syntaxtree.ReturnStmt) any {
+	if stmt.Value == nil {
+		return returnSignal{value: RoseNil{}}
+	}
+	return returnSignal{value: s.number(stmt.Value)}
+}
+
 func (s *intepreter) VisitForStmt(stmt syntaxtree.ForStmt) any {
-	for s.eval(stmt.PreStatement); s.number(stmt.Condition).(RoseBool).value; s.number(stmt.PostStatement) {
-		s.eval(stmt.Block)
+	prev := s.sc
+	s.sc = newScope(prev)
+	defer func() { s.sc = prev }()
+	for s.eval(stmt.PreStatement); isTruthy(s.number(stmt.Condition)); s.number(stmt.PostStatement) {
+		if signal, stop := loopSignal(s.eval(stmt.Block), stmt.Label); stop {
+			return signal
+		}
 	}
 	return nil
 }
 
+func (s *intepreter) VisitWhileStmt(stmt syntaxtree.WhileStmt) any {
+	for {
+		if !isTruthy(s.number(stmt.Condition)) {
+			return nil
+		}
+		if signal, stop := loopSignal(s.eval(stmt.Block), stmt.Label); stop {
+			return signal
+		}
+	}
+}
+
+func (s *intepreter) VisitBreakStmt(stmt syntaxtree.BreakStmt) any {
+	return breakSignal{label: stmt.Label.Content}
+}
+
+func (s *intepreter) VisitContinueStmt(stmt syntaxtree.ContinueStmt) any {
+	return continueSignal{label: stmt.Label.Content}
+}
+
+// loopSignal decides what a loop labeled with label does with the signal its body produced:
+// stop reports whether the loop ends, signal is what the loop has to pass further up
+func loopSignal(signal any, label tokenizer.Token) (any, bool) {
+	switch val := signal.(type) {
+	case nil:
+		return nil, false
+	case breakSignal:
+		if val.label == "" || val.label == label.Content {
+			return nil, true
+		}
+	case continueSignal:
+		if val.label == "" || val.label == label.Content {
+			return nil, false
+		}
+	}
+	return signal, true
+}
+
 func (s *intepreter) VisitIfStmt(stmt syntaxtree.IfStmt) any {
 	cond := s.number(stmt.Condition)
-	if val, ok := cond.(RoseBool); ok {
-		if val.value {
-			s.eval(stmt.Block)
+	if _, ok := cond.(RuntimeError); ok {
+		return nil
+	}
+	if isTruthy(cond) {
+		return s.eval(stmt.Block)
+	} else if stmt.Else != nil {
+		return s.eval(stmt.Else)
+	}
+	return nil
+}
+
+// VisitMatchStmt runs the first arm whose pattern matches and whose guard holds, bindings live in a scope of the arm
+func (s *intepreter) VisitMatchStmt(stmt syntaxtree.MatchStmt) any {
+	value := s.number(stmt.Value)
+	if _, ok := value.(RuntimeError); ok {
+		return nil
+	}
+	for i, v := range stmt.Patterns {
+		bindings := make(map[string]RoseType)
+		if !(matcher{interp: s, bindings: bindings}).match(v, value) {
+			continue
+		}
+		prev := s.sc
+		s.sc = &scope{vars: bindings, parent: prev}
+		if stmt.Guards[i] != nil && !isTruthy(s.number(stmt.Guards[i])) {
+			s.sc = prev
+			continue
 		}
+		signal := s.eval(stmt.Bodies[i])
+		s.sc = prev
+		return signal
 	}
+	fmt.Println("RUNTIME ERROR: no match arm for " + repr(value))
 	return nil
 }
 
 func (s *intepreter) VisitBlockStmt(stmt syntaxtree.BlockStmt) any {
 	prev := s.sc
-	s.sc = scope{vars: make(map[string]RoseType), parent: &prev}
+	s.sc = newScope(prev)
+	defer func() { s.sc = prev }()
+	s.declare(stmt.Statements)
 	for _, v := range stmt.Statements {
-		s.eval(v)
+		if hoisted(v) {
+			continue
+		}
+		if signal := s.eval(v); signal != nil {
+			return signal
+		}
 	}
-	s.sc = prev
 	return nil
 }
@@ -0,0 +1,205 @@
+package interpreter
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/WhoDoIt/GoCompiler/internal/parser"
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+func parse(t *testing.T, source string) []syntaxtree.Stmt {
+	tokens, err := tokenizer.Tokenize([]byte(source))
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	tree, err := parser.Parse(tokens)
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	return tree
+}
+
+// run evaluates a program and returns what it printed, one entry per line without the "> " of print
+func run(t *testing.T, tree []syntaxtree.Stmt) []string {
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	Evaluate(tree)
+	os.Stdout = stdout
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, v := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		lines = append(lines, strings.TrimPrefix(v, ">  "))
+	}
+	return lines
+}
+
+func expectOutput(t *testing.T, source string, output ...string) {
+	t.Helper()
+	if got := run(t, parse(t, source)); strings.Join(got, "\n") != strings.Join(output, "\n") {
+		t.Errorf("%s:\ngot  %q\nwant %q", source, got, output)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	expectOutput(t, "fn add(a, b) { print a + b; } add(1, 2); print add;", "3", "<fn add>")
+	expectOutput(t, "var x = 1; fn show() { print x; } x = 2; show();", "2")
+	expectOutput(t, "fn add(a, b) {} add(1);", "RUNTIME ERROR: <fn add> expected 2 arguments but got 1")
+	expectOutput(t, "f(); fn f() { print 1; }", "1")
+	expectOutput(t, "fn g(a) { print a; } g(1 / 0); var h = (a) => a; print h(1 / 0);", "RUNTIME ERROR: integer division by zero", "RUNTIME ERROR: integer division by zero", "RUNTIME ERROR: integer division by zero", "RUNTIME ERROR: integer division by zero", "integer division by zero")
+	expectOutput(t, "fn even(n) { return n == 0 or odd(n - 1); } fn odd(n) { return n != 0 and even(n - 1); } print even(4); { print P(2).x; struct P { x } }", "true", "2")
+}
+
+// TestFunctionWithoutResult checks that a call whose body ends without a value gives nil instead of crashing
+func TestFunctionWithoutResult(t *testing.T) {
+	expectOutput(t, "fn f() {} print f(); print f() + 1;", "nil", "RUNTIME ERROR: unsupported operation of (Nil and Int)", "unsupported operation of (Nil and Int)")
+}
+
+func TestReturn(t *testing.T) {
+	expectOutput(t, "fn fib(n) { if (n < 2) { return n; } return fib(n - 1) + fib(n - 2); } print fib(10);", "55")
+	expectOutput(t, "fn first() { for (var i = 0; i < 10; i = i + 1) { if (i == 3) { return i; } } } print first();", "3")
+	expectOutput(t, "fn early() { return; print 1; } print early() == early();", "true")
+}
+
+func TestElse(t *testing.T) {
+	source := "fn sign(n) { if (n < 0) { return 0 - 1; } else if (n == 0) { return 0; } else { return 1; } }"
+	expectOutput(t, source+" print sign(0 - 5); print sign(0); print sign(5);", "-1", "0", "1")
+	expectOutput(t, "if (1 == 2) { print 1; } else if (1 == 3) { print 2; }")
+}
+
+func TestLoops(t *testing.T) {
+	expectOutput(t, "var i = 0; while (i < 3) { i = i + 1; } print i;", "3")
+	expectOutput(t, "var i = 0; while (1 == 1) { i = i + 1; if (i < 3) { continue; } break; } print i;", "3")
+	expectOutput(t, "var i = 0; outer: while (1 == 1) { while (1 == 1) { i = i + 1; if (i == 3) { break outer; } continue outer; } } print i;", "3")
+	expectOutput(t, "outer: for (var i = 0; i < 3; i = i + 1) { for (var j = 0; j < 3; j = j + 1) { if (j == 1) { continue outer; } print i; } }", "0", "1", "2")
+}
+
+func TestLogical(t *testing.T) {
+	expectOutput(t, "fn loud(v) { print v; return v; } print 1 == 2 and loud(1 == 1); print 1 == 1 or loud(1 == 1);", "false", "true")
+	expectOutput(t, "fn loud(v) { print v; return v; } print 1 == 1 and loud(1 == 2);", "false", "false")
+	expectOutput(t, "print 6 & 3; print 6 | 3;", "2", "7")
+}
+
+func TestTruthiness(t *testing.T) {
+	tests := []struct {
+		value  string
+		truthy bool
+	}{
+		{"true", true},
+		{"false", false},
+		{"nil", false},
+		{"0", true},
+		{"\"\"", true},
+		{"1 < 2", true},
+	}
+	for _, test := range tests {
+		output := run(t, parse(t, "if ("+test.value+") { print 1; } else { print 0; }"))
+		if (output[0] == "1") != test.truthy {
+			t.Errorf("%s: got %s", test.value, output[0])
+		}
+	}
+	expectOutput(t, "print nil == nil; print nil == false; print !nil; print 3 > 2;", "true", "false", "true", "true")
+}
+
+func TestFloats(t *testing.T) {
+	expectOutput(t, "print 7 / 2; print 7 / 2.0; print 1 + 0.5; print 2.0 == 2; print 1.5 < 2;", "3", "3.5", "1.5", "true", "true")
+	expectOutput(t, "print int(3.9); print int(0 - 3.9); print float(2); print int(\"12\"); print float(\"x\");", "3", "-3", "2.0", "12", "RUNTIME ERROR: cannot convert \"x\" to Float", "cannot convert \"x\" to Float")
+}
+
+func TestStructs(t *testing.T) {
+	expectOutput(t, "struct P { x, y } var p = P(1, 2); p.y = p.x + 4; print p.x; print p.y;", "1", "5")
+	expectOutput(t, "struct P { x } var a = P(1); var b = a; b.x = 2; print a.x;", "2")
+	expectOutput(t, "struct P { x } print P(1).z; P(1, 2);", "RUNTIME ERROR: P has no field z", "P has no field z", "RUNTIME ERROR: P expected 1 arguments but got 2")
+}
+
+func TestMethods(t *testing.T) {
+	expectOutput(t, "struct C { n fn inc() { self.n = self.n + 1; return self.n; } } var c = C(0); c.inc(); var inc = c.inc; print inc(); print c.n;", "2", "2")
+	expectOutput(t, "struct V { x fn plus(o) { return V(self.x + o.x); } } print V(1).plus(V(2)).x;", "3")
+}
+
+func TestLists(t *testing.T) {
+	expectOutput(t, "var xs = [1, 2, 3]; xs[0] = 5; print xs[0]; print xs[-1]; print len(xs[1:]); print len(xs + [4]);", "5", "3", "2", "4")
+	expectOutput(t, "var xs = []; var ys = xs; push(ys, 1); print len(xs); print pop(xs); print len(xs);", "1", "1", "0")
+	expectOutput(t, "var xs = [1]; print xs[1]; print pop([]);", "RUNTIME ERROR: list index 1 out of range for length 1", "list index 1 out of range for length 1", "RUNTIME ERROR: pop from empty list", "pop from empty list")
+}
+
+func TestMaps(t *testing.T) {
+	expectOutput(t, "var m = {\"b\": 1, \"a\": 2}; m[\"c\"] = 3; m[\"b\"] = 4; print keys(m)[0]; print values(m)[0]; print len(keys(m));", "b", "4", "3")
+	expectOutput(t, "var m = {1: \"int\", 1.0: \"float\", true: \"bool\"}; print len(keys(m)); print \"a\" in {\"a\": nil}; print 2 in [1, 2];", "2", "true", "true")
+	expectOutput(t, "var m = {\"a\": 1, \"b\": 2}; delete(m, \"a\"); print \"a\" in m; print keys(m)[0]; print m[\"zz\"];", "false", "b", "RUNTIME ERROR: key \"zz\" not found in map", "key \"zz\" not found in map")
+	expectOutput(t, "print {[1]: 1};", "RUNTIME ERROR: unhashable map key of type List", "unhashable map key of type List")
+}
+
+func TestInterpolation(t *testing.T) {
+	expectOutput(t, "var n = 2; print \"a${n + 1}b${\"c\"}\";", "a3bc")
+	expectOutput(t, "struct P { x } print \"${[1, \"s\", nil, 2.5]} ${{\"k\": true}} ${P(1)}\";", "[1, \"s\", nil, 2.5] {\"k\": true} P{x: 1}")
+	expectOutput(t, "print \"nested ${\"in ${1 + 1}\"}\"; print \"\\${x}\";", "nested in 2", "${x}")
+}
+
+func TestNumberLiterals(t *testing.T) {
+	expectOutput(t, "print 0x10 + 0b11 + 0o7; print 1_000 * 2; print 1e3; print 2.5e-1;", "26", "2000", "1000.0", "0.25")
+}
+
+func TestOperators(t *testing.T) {
+	tests := []struct {
+		expr  string
+		value string
+	}{
+		{"7 % 3", "1"},
+		{"-7 % 3", "-1"},
+		{"7.5 % 2", "1.5"},
+		{"2 ** 10", "1024"},
+		{"2 ** -1", "0.5"},
+		{"-2 ** 2", "-4"},
+		{"2 ** 3 ** 2", "512"},
+		{"1 << 4 >> 2", "4"},
+		{"6 ^ 3", "5"},
+		{"~5", "-6"},
+		{"1 + 2 << 1", "6"},
+		{"7 % 0", "integer modulo by zero"},
+	}
+	for _, test := range tests {
+		if got := run(t, parse(t, "print "+test.expr+";")); got[len(got)-1] != test.value {
+			t.Errorf("%s: got %q, want %s", test.expr, got, test.value)
+		}
+	}
+	expectOutput(t, "var i = 1; i += 2; i *= 3; i -= 1; i /= 2; i %= 3; print i; i++; print i; i--; i--; print i;", "1", "2", "0")
+	expectOutput(t, "print true & false; print true | false; print true ^ true;", "false", "true", "false")
+	expectOutput(t, "var xs = [1]; xs[0] += 5; xs[0]++; print xs; struct P { n } var p = P(1); p.n *= 4; p.n--; print p.n;", "[7]", "3")
+}
+
+func TestConditional(t *testing.T) {
+	expectOutput(t, "fn size(n) { return n < 10 ? \"small\" : n < 100 ? \"medium\" : \"large\"; } print size(5); print size(50); print size(500);", "small", "medium", "large")
+	expectOutput(t, "fn loud(v) { print v; return v; } print true ? loud(1) : loud(2); print nil ? 1 : 2;", "1", "1", "2")
+}
+
+func TestClosures(t *testing.T) {
+	expectOutput(t, "fn counter() { var n = 0; return () => n += 1; } var a = counter(); var b = counter(); a(); a(); print a(); print b();", "3", "1")
+	expectOutput(t, "var twice = fn(f, x) { return f(f(x)); }; print twice((x) => x * 3, 2);", "18")
+	expectOutput(t, "var fs = []; for (var i = 0; i < 3; i++) { var j = i; push(fs, () => j); } print fs[0]() + fs[2]();", "2")
+	expectOutput(t, "fn outer() { var x = 1; fn get() { return x; } x = 5; return get; } print outer()();", "5")
+}
+
+func TestTraits(t *testing.T) {
+	expectOutput(t, "struct P { x fn show() -> String { return \"P${self.x}\"; } } print P(1); print [P(2)]; print \"${P(3)}\";", "P1", "[P2]", "P3")
+	expectOutput(t, "struct M { n fn eq(o) { return self.n == o.n; } fn less(o) { return self.n < o.n; } } print M(1) == M(1); print M(1) != M(2); print M(1) < M(2); print M(3) >= M(4);", "true", "true", "true", "false")
+	expectOutput(t, "trait T { fn name(); fn hi() { return \"hi \" + self.name(); } } struct A : T { fn name() { return \"a\"; } } struct B : T { fn name() { return \"b\"; } fn hi() { return \"yo\"; } } print A().hi(); print B().hi();", "hi a", "yo")
+	expectOutput(t, "struct P : Ord { n fn less(o) { return self.n < o.n; } fn show() { return \"P${self.n}\"; } } fn max[T: Ord](a: T, b: T) -> T { return a < b ? b : a; } print max(P(2), P(1));", "P2")
+}
+
+func TestBindings(t *testing.T) {
+	expectOutput(t, "let x = 1; x = 2; print x;", "RUNTIME ERROR: cannot assign to immutable x", "1")
+	expectOutput(t, "var x = 1; { let x = 2; } x = 3; print x;", "3")
+	expectOutput(t, "const N = 2; const M = N * 3 + 1; print M; print \"${N}${M}\";", "7", "27")
+}
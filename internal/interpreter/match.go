@@ -0,0 +1,90 @@
+package interpreter
+
+import (
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+)
+
+// matcher checks a value against a pattern, collecting the variables the pattern binds
+type matcher struct {
+	interp   *intepreter
+	value    RoseType
+	bindings map[string]RoseType
+}
+
+func (m matcher) match(pattern syntaxtree.Pattern, value RoseType) bool {
+	return syntaxtree.AcceptPattern(matcher{interp: m.interp, value: value, bindings: m.bindings}, pattern)
+}
+
+func (m matcher) VisitLiteralPattern(pattern syntaxtree.LiteralPattern) bool {
+	return isTruthy(equalValues(m.value, m.interp.number(pattern.Value)))
+}
+
+func (m matcher) VisitWildcardPattern(pattern syntaxtree.WildcardPattern) bool {
+	return true
+}
+
+func (m matcher) VisitBindingPattern(pattern syntaxtree.BindingPattern) bool {
+	m.bindings[pattern.Name.Content] = m.value
+	return true
+}
+
+// VisitAlternativePattern keeps only the bindings of the first alternative that matches
+func (m matcher) VisitAlternativePattern(pattern syntaxtree.AlternativePattern) bool {
+	for _, v := range pattern.Alternatives {
+		alternative := matcher{interp: m.interp, bindings: make(map[string]RoseType)}
+		if alternative.match(v, m.value) {
+			for name, val := range alternative.bindings {
+				m.bindings[name] = val
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (m matcher) VisitListPattern(pattern syntaxtree.ListPattern) bool {
+	list, ok := m.value.(*RoseList)
+	if !ok || len(list.elements) != len(pattern.Elements) {
+		return false
+	}
+	for i, v := range pattern.Elements {
+		if !m.match(v, list.elements[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// VisitEnumPattern only checks the fields when the pattern lists them
+func (m matcher) VisitEnumPattern(pattern syntaxtree.EnumPattern) bool {
+	value, ok := m.value.(*RoseEnumValue)
+	if !ok || value.enum.name != pattern.Enum.Content || value.variant != pattern.Variant.Content {
+		return false
+	}
+	if pattern.Patterns == nil {
+		return true
+	}
+	if len(pattern.Patterns) != len(value.values) {
+		return false
+	}
+	for i, v := range pattern.Patterns {
+		if !m.match(v, value.values[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m matcher) VisitStructPattern(pattern syntaxtree.StructPattern) bool {
+	instance, ok := m.value.(*RoseInstance)
+	if !ok || instance.structType.name != pattern.Name.Content {
+		return false
+	}
+	for i, v := range pattern.Fields {
+		field, ok := instance.fields[v.Content]
+		if !ok || !m.match(pattern.Patterns[i], field) {
+			return false
+		}
+	}
+	return true
+}
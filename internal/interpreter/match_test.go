@@ -0,0 +1,25 @@
+package interpreter
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		source string
+		output []string
+	}{
+		{"match (2) { 1 => print \"one\", 2 | 3 => print \"two or three\", _ => print \"other\" }", []string{"two or three"}},
+		{"match (-1) { -1 => print \"minus one\", _ => {} }", []string{"minus one"}},
+		{"match (\"b\") { \"a\" => print 1, s => print s }", []string{"b"}},
+		{"match ([1, 2]) { [a] => print a, [a, b] if a > b => print a, [a, b] => print b }", []string{"2"}},
+		{"match ([1, [2, 3]]) { [_, [x, _]] => print x, _ => print 0 }", []string{"2"}},
+		{"struct P { x, y } match (P(1, 2)) { P { x: 0 } => print 0, P { x, y } => print x + y }", []string{"3"}},
+		{"match (nil) { true => print 1, false => print 2 }", []string{"RUNTIME ERROR: no match arm for nil"}},
+		{"var x = 1; match (2) { x => print x } print x;", []string{"2", "1"}},
+		{"enum Shape { Circle(r), Rect(w, h), Empty } var s = Shape.Rect(2, 3); print s; match (s) { Shape.Circle(r) => print r, Shape.Rect(w, h) => print w * h, Shape.Empty => print 0 }", []string{"Shape.Rect(2, 3)", "6"}},
+		{"enum Color { Red, Green } print Color.Red == Color.Red; print Color.Red == Color.Green; print Color.Green;", []string{"true", "false", "Color.Green"}},
+		{"enum Shape { Circle(r) } print Shape.Circle(1) == Shape.Circle(1); print Shape.Circle(1, 2); print Shape.Square;", []string{"true", "RUNTIME ERROR: Shape.Circle expected 1 arguments but got 2", "Shape.Circle expected 1 arguments but got 2", "RUNTIME ERROR: Shape has no variant Square", "Shape has no variant Square"}},
+	}
+	for _, test := range tests {
+		expectOutput(t, test.source, test.output...)
+	}
+}
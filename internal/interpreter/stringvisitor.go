@@ -1,6 +1,8 @@
 package interpreter
 
 import (
+	"strings"
+
 	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
 )
 
@@ -26,6 +28,14 @@ func (s StringVisitor) VisitBinaryExpr(expr syntaxtree.BinaryExpr) string {
 	return s.string(expr.Operator.Content, []syntaxtree.Expr{expr.Left, expr.Right})
 }
 
+func (s StringVisitor) VisitConditionalExpr(expr syntaxtree.ConditionalExpr) string {
+	return s.string("?:", []syntaxtree.Expr{expr.Condition, expr.Then, expr.Else})
+}
+
+func (s StringVisitor) VisitLogicalExpr(expr syntaxtree.LogicalExpr) string {
+	return s.string(expr.Operator.Content, []syntaxtree.Expr{expr.Left, expr.Right})
+}
+
 func (s StringVisitor) VisitUnaryExpr(expr syntaxtree.UnaryExpr) string {
 	return s.string(expr.Operator.Content, []syntaxtree.Expr{expr.Right})
 }
@@ -38,6 +48,57 @@ func (s StringVisitor) VisitLiteralExpr(expr syntaxtree.LiteralExpr) string {
 	return expr.Value.Content
 }
 
+func (s StringVisitor) VisitInterpolatedStringExpr(expr syntaxtree.InterpolatedStringExpr) string {
+	return s.string("interpolate", expr.Parts)
+}
+
+func (s StringVisitor) VisitFunctionExpr(expr syntaxtree.FunctionExpr) string {
+	var params []string
+	for _, v := range expr.Params {
+		params = append(params, v.Content)
+	}
+	return "(fn (" + strings.Join(params, " ") + "))"
+}
+
 func (s StringVisitor) VisitCallExpr(expr syntaxtree.CallExpr) string {
 	return s.string("call $"+s.Print(expr.Calle), expr.Arguments)
 }
+
+func (s StringVisitor) VisitGetExpr(expr syntaxtree.GetExpr) string {
+	return s.string("get ."+expr.Name.Content, []syntaxtree.Expr{expr.Object})
+}
+
+func (s StringVisitor) VisitSetExpr(expr syntaxtree.SetExpr) string {
+	return s.string(expr.Operator.Content+" ."+expr.Name.Content, []syntaxtree.Expr{expr.Object, expr.Value})
+}
+
+func (s StringVisitor) VisitListExpr(expr syntaxtree.ListExpr) string {
+	return s.string("list", expr.Elements)
+}
+
+func (s StringVisitor) VisitIndexExpr(expr syntaxtree.IndexExpr) string {
+	return s.string("index", []syntaxtree.Expr{expr.Object, expr.Index})
+}
+
+func (s StringVisitor) VisitIndexSetExpr(expr syntaxtree.IndexSetExpr) string {
+	return s.string(expr.Operator.Content+" index", []syntaxtree.Expr{expr.Object, expr.Index, expr.Value})
+}
+
+func (s StringVisitor) VisitSliceExpr(expr syntaxtree.SliceExpr) string {
+	start, end := "_", "_"
+	if expr.Start != nil {
+		start = s.Print(expr.Start)
+	}
+	if expr.End != nil {
+		end = s.Print(expr.End)
+	}
+	return "(slice " + s.Print(expr.Object) + " " + start + " " + end + ")"
+}
+
+func (s StringVisitor) VisitMapExpr(expr syntaxtree.MapExpr) string {
+	var entries []syntaxtree.Expr
+	for i := range expr.Keys {
+		entries = append(entries, expr.Keys[i], expr.Values[i])
+	}
+	return s.string("map", entries)
+}
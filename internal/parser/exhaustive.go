@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+// exhaustive warns when a match over a closed set of values (bools, variants of an enum) leaves some of them unhandled,
+// arms with a guard never count as covering a value
+func (p *parser) exhaustive(stmt syntaxtree.MatchStmt) {
+	covered := make(map[string]bool)
+	var closed []string
+	for i, v := range stmt.Patterns {
+		if closed == nil {
+			closed = p.closedSet(v)
+		}
+		if stmt.Guards[i] != nil {
+			continue
+		}
+		if coverPattern(v, covered) {
+			return
+		}
+	}
+	var missing []string
+	for _, v := range closed {
+		if !covered[v] {
+			missing = append(missing, v)
+		}
+	}
+	if missing != nil {
+		p.warn("match is not exhaustive, missing "+strings.Join(missing, ", "), stmt.Keyword)
+	}
+}
+
+// coverPattern records the closed set values the pattern matches entirely and reports whether it matches anything
+func coverPattern(pattern syntaxtree.Pattern, covered map[string]bool) bool {
+	switch pattern := pattern.(type) {
+	case syntaxtree.WildcardPattern, syntaxtree.BindingPattern:
+		return true
+	case syntaxtree.LiteralPattern:
+		if literal, ok := pattern.Value.(syntaxtree.LiteralExpr); ok && (literal.Value.Type == tokenizer.TRUE || literal.Value.Type == tokenizer.FALSE) {
+			covered[literal.Value.Content] = true
+		}
+	case syntaxtree.EnumPattern:
+		for _, v := range pattern.Patterns {
+			if !coverPattern(v, make(map[string]bool)) {
+				return false
+			}
+		}
+		covered[pattern.Enum.Content+"."+pattern.Variant.Content] = true
+	case syntaxtree.AlternativePattern:
+		all := false
+		for _, v := range pattern.Alternatives {
+			all = coverPattern(v, covered) || all
+		}
+		return all
+	}
+	return false
+}
+
+// closedSet returns every value of the closed set the pattern belongs to, nil when it matches an open set
+func (p *parser) closedSet(pattern syntaxtree.Pattern) []string {
+	switch pattern := pattern.(type) {
+	case syntaxtree.LiteralPattern:
+		if literal, ok := pattern.Value.(syntaxtree.LiteralExpr); ok && (literal.Value.Type == tokenizer.TRUE || literal.Value.Type == tokenizer.FALSE) {
+			return []string{"true", "false"}
+		}
+	case syntaxtree.EnumPattern:
+		if decl, ok := p.enums[pattern.Enum.Content]; ok {
+			var set []string
+			for _, v := range decl.Variants {
+				set = append(set, decl.Name.Content+"."+v.Content)
+			}
+			return set
+		}
+	case syntaxtree.AlternativePattern:
+		for _, v := range pattern.Alternatives {
+			if set := p.closedSet(v); set != nil {
+				return set
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) warn(str string, token tokenizer.Token) {
+	p.warnings = append(p.warnings, "WARNING: ["+str+"] on line "+strconv.Itoa(token.Line))
+}
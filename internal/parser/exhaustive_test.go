@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+func warnings(t *testing.T, source string) string {
+	tokens, err := tokenizer.Tokenize([]byte(source))
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	p := parser{tokens: tokens, enums: make(map[string]syntaxtree.EnumDeclStmt)}
+	for !p.isAtEnd() {
+		if _, err := p.declaration(); err != nil {
+			t.Fatalf("%s: %v", source, err)
+		}
+	}
+	return strings.Join(p.warnings, "\n")
+}
+
+func TestExhaustive(t *testing.T) {
+	tests := []struct {
+		source  string
+		warning string
+	}{
+		{"match (true) { true => print 1, false => print 2 }", ""},
+		{"match (true) { true => print 1 }", "WARNING: [match is not exhaustive, missing false] on line 1"},
+		{"match (true) { true | false => print 1 }", ""},
+		{"match (true) { true => print 1, false if 1 > 2 => print 2 }", "WARNING: [match is not exhaustive, missing false] on line 1"},
+		{"match (true) { false => print 1, b => print b }", ""},
+		{"match (1) { 1 => print 1 }", ""},
+		{"enum Shape { Circle(r), Square(s), Empty } fn f(s) { match (s) { Shape.Circle(r) => print r, Shape.Empty => print 0 } }", "WARNING: [match is not exhaustive, missing Shape.Square] on line 1"},
+		{"enum Shape { Circle(r), Empty } fn f(s) { match (s) { Shape.Circle(0) => print 0, Shape.Empty => print 0 } }", "WARNING: [match is not exhaustive, missing Shape.Circle] on line 1"},
+		{"enum Shape { Circle(r), Empty } fn f(s) { match (s) { Shape.Circle(_) | Shape.Empty => print 0 } }", ""},
+		{"match (false) { true => print 1, \"false\" => print 2 }", "WARNING: [match is not exhaustive, missing false] on line 1"},
+	}
+	for _, test := range tests {
+		if got := warnings(t, test.source); got != test.warning {
+			t.Errorf("%s: got %q, want %q", test.source, got, test.warning)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"strconv"
 
 	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
@@ -10,8 +11,16 @@ import (
 )
 
 type parser struct {
-	tokens  []tokenizer.Token
-	current int
+	tokens    []tokenizer.Token
+	current   int
+	funcDepth int
+	// labels of the loops enclosing the current statement, "" for unlabeled ones
+	loops []string
+	// set while parsing the statement of a match arm, which may end at the , or } closing the arm
+	inArm bool
+	// enums declared so far, used to check enum patterns
+	enums    map[string]syntaxtree.EnumDeclStmt
+	warnings []string
 }
 
 func (p *parser) isAtEnd() bool {
@@ -26,6 +35,13 @@ func (p *parser) peek() tokenizer.Token {
 	return p.tokens[p.current]
 }
 
+func (p *parser) peekNext() tokenizer.Token {
+	if p.isAtEnd() {
+		return p.peek()
+	}
+	return p.tokens[p.current+1]
+}
+
 func (p *parser) advance() tokenizer.Token {
 	if !p.isAtEnd() {
 		p.current++
@@ -72,7 +88,7 @@ func (p *parser) synchronize() {
 			return
 		}
 		switch p.peek().Type {
-		case tokenizer.FN, tokenizer.VAR, tokenizer.IF, tokenizer.ELSE, tokenizer.RETURN, tokenizer.FOR, tokenizer.STRUCT, tokenizer.PRINT:
+		case tokenizer.FN, tokenizer.VAR, tokenizer.IF, tokenizer.ELSE, tokenizer.RETURN, tokenizer.FOR, tokenizer.WHILE, tokenizer.STRUCT, tokenizer.PRINT, tokenizer.MATCH, tokenizer.ENUM, tokenizer.TRAIT, tokenizer.LET, tokenizer.CONST:
 			return
 		}
 		p.advance()
@@ -80,7 +96,7 @@ func (p *parser) synchronize() {
 }
 
 func Parse(tokens []tokenizer.Token) ([]syntaxtree.Stmt, error) {
-	parser := parser{tokens: tokens}
+	parser := parser{tokens: tokens, enums: make(map[string]syntaxtree.EnumDeclStmt)}
 	var tree []syntaxtree.Stmt
 	var errs []error
 	for !parser.isAtEnd() {
@@ -90,6 +106,9 @@ func Parse(tokens []tokenizer.Token) ([]syntaxtree.Stmt, error) {
 		}
 		tree = append(tree, stmt)
 	}
+	for _, v := range parser.warnings {
+		fmt.Println(v)
+	}
 	for _, v := range errs {
 		fmt.Println(v.Error())
 	}
@@ -103,8 +122,16 @@ func Parse(tokens []tokenizer.Token) ([]syntaxtree.Stmt, error) {
 func (p *parser) declaration() (syntaxtree.Stmt, error) {
 	var stmt syntaxtree.Stmt
 	var err error
-	if p.check(tokenizer.VAR) {
+	if p.checkMany([]tokenizer.TokenType{tokenizer.VAR, tokenizer.LET, tokenizer.CONST}) {
 		stmt, err = p.varDelc()
+	} else if p.check(tokenizer.FN) && p.peekNext().Type == tokenizer.IDENTIFIER {
+		stmt, err = p.funcDecl()
+	} else if p.check(tokenizer.STRUCT) {
+		stmt, err = p.structDecl()
+	} else if p.check(tokenizer.ENUM) {
+		stmt, err = p.enumDecl()
+	} else if p.check(tokenizer.TRAIT) {
+		stmt, err = p.traitDecl()
 	} else {
 		stmt, err = p.statement()
 	}
@@ -117,15 +144,24 @@ func (p *parser) declaration() (syntaxtree.Stmt, error) {
 }
 
 func (p *parser) varDelc() (syntaxtree.Stmt, error) {
-	// ASSUME VAR ALREADY CHECKED
-	if !p.check(tokenizer.VAR) {
+	// let and const declare bindings that cannot be assigned to
+	if !p.checkMany([]tokenizer.TokenType{tokenizer.VAR, tokenizer.LET, tokenizer.CONST}) {
 		return nil, p.generateError("expected var")
 	}
-	p.advance()
+	keyword := p.advance()
 	name := p.advance()
 	if name.Type != tokenizer.IDENTIFIER {
 		return nil, p.generateError("bad name for var")
 	}
+	var typ syntaxtree.TypeExpr
+	if p.check(tokenizer.COLON) {
+		p.advance()
+		var err error
+		typ, err = p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !p.check(tokenizer.EQUAL) {
 		return nil, p.generateError("expected =")
 	}
@@ -138,8 +174,462 @@ func (p *parser) varDelc() (syntaxtree.Stmt, error) {
 		return nil, p.generateError("expected ;")
 	}
 	p.advance()
-	return syntaxtree.VarDeclStmt{Name: name, Expression: expr}, nil
+	return syntaxtree.VarDeclStmt{Keyword: keyword, Name: name, Type: typ, Expression: expr}, nil
+
+}
+
+func (p *parser) funcDecl() (syntaxtree.Stmt, error) {
+	stmt, err := p.funcSignature()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Body, err = p.functionBody()
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// funcSignature parses everything of a fn declaration up to its body
+func (p *parser) funcSignature() (syntaxtree.FuncDeclStmt, error) {
+	p.advance()
+	name := p.advance()
+	if name.Type != tokenizer.IDENTIFIER {
+		return syntaxtree.FuncDeclStmt{}, p.generateError("bad name for fn")
+	}
+	typeParams, bounds, err := p.typeParameters()
+	if err != nil {
+		return syntaxtree.FuncDeclStmt{}, err
+	}
+	params, types, err := p.parameters()
+	if err != nil {
+		return syntaxtree.FuncDeclStmt{}, err
+	}
+	ret, err := p.returnType()
+	if err != nil {
+		return syntaxtree.FuncDeclStmt{}, err
+	}
+	return syntaxtree.FuncDeclStmt{Name: name, TypeParams: typeParams, Bounds: bounds, Params: params, ParamTypes: types, Return: ret}, nil
+}
+
+// typeParameters parses [T, U: A + B], every type parameter comes with the traits it is bound by
+func (p *parser) typeParameters() ([]tokenizer.Token, [][]tokenizer.Token, error) {
+	if !p.check(tokenizer.LEFT_BRACKET) {
+		return nil, nil, nil
+	}
+	p.advance()
+	var params []tokenizer.Token
+	var bounds [][]tokenizer.Token
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACKET) {
+		param := p.advance()
+		if param.Type != tokenizer.IDENTIFIER {
+			return nil, nil, p.generateError("bad name for type parameter")
+		}
+		for _, v := range params {
+			if v.Content == param.Content {
+				return nil, nil, p.generateError("duplicate type parameter " + param.Content)
+			}
+		}
+		var traits []tokenizer.Token
+		if p.check(tokenizer.COLON) {
+			p.advance()
+			for {
+				trait := p.advance()
+				if trait.Type != tokenizer.IDENTIFIER {
+					return nil, nil, p.generateError("bad name for trait")
+				}
+				traits = append(traits, trait)
+				if !p.check(tokenizer.PLUS) {
+					break
+				}
+				p.advance()
+			}
+		}
+		params = append(params, param)
+		bounds = append(bounds, traits)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		return nil, nil, p.generateError("expected ] after type parameters")
+	}
+	p.advance()
+	if params == nil {
+		return nil, nil, p.generateError("empty type parameter list")
+	}
+	return params, bounds, nil
+}
+
+// parameters parses a parenthesized list of parameter names, each optionally followed by : and its type
+func (p *parser) parameters() ([]tokenizer.Token, []syntaxtree.TypeExpr, error) {
+	if !p.check(tokenizer.LEFT_PAREN) {
+		return nil, nil, p.generateError("expected ( before parameters")
+	}
+	p.advance()
+	var params []tokenizer.Token
+	var types []syntaxtree.TypeExpr
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_PAREN) {
+		param := p.advance()
+		if param.Type != tokenizer.IDENTIFIER {
+			return nil, nil, p.generateError("bad name for parameter")
+		}
+		var typ syntaxtree.TypeExpr
+		if p.check(tokenizer.COLON) {
+			p.advance()
+			var err error
+			typ, err = p.typeExpr()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		params = append(params, param)
+		types = append(types, typ)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_PAREN) {
+		return nil, nil, p.generateError("expected ) after parameters")
+	}
+	p.advance()
+	return params, types, nil
+}
+
+// returnType parses an optional -> Type after parameters
+func (p *parser) returnType() (syntaxtree.TypeExpr, error) {
+	if !p.check(tokenizer.MINUS_GREATER) {
+		return nil, nil
+	}
+	p.advance()
+	return p.typeExpr()
+}
+
+// typeExpr parses Name, Name[Type, ...] or fn(Type, ...) -> Type
+func (p *parser) typeExpr() (syntaxtree.TypeExpr, error) {
+	if p.check(tokenizer.FN) {
+		keyword := p.advance()
+		if !p.check(tokenizer.LEFT_PAREN) {
+			return nil, p.generateError("expected ( after fn in type")
+		}
+		p.advance()
+		var params []syntaxtree.TypeExpr
+		for !p.isAtEnd() && !p.check(tokenizer.RIGHT_PAREN) {
+			param, err := p.typeExpr()
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, param)
+			if !p.check(tokenizer.COMMA) {
+				break
+			}
+			p.advance()
+		}
+		if !p.check(tokenizer.RIGHT_PAREN) {
+			return nil, p.generateError("expected ) after parameter types")
+		}
+		p.advance()
+		ret, err := p.returnType()
+		if err != nil {
+			return nil, err
+		}
+		return syntaxtree.FunctionType{Keyword: keyword, Params: params, Return: ret}, nil
+	}
+	name := p.advance()
+	if name.Type != tokenizer.IDENTIFIER {
+		return nil, p.generateError("bad type name")
+	}
+	typ := syntaxtree.NamedType{Name: name}
+	if !p.check(tokenizer.LEFT_BRACKET) {
+		return typ, nil
+	}
+	p.advance()
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACKET) {
+		argument, err := p.typeExpr()
+		if err != nil {
+			return nil, err
+		}
+		typ.Arguments = append(typ.Arguments, argument)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		return nil, p.generateError("expected ] after type arguments")
+	}
+	p.advance()
+	return typ, nil
+}
+
+// functionBody parses a fn body block, loops outside of the fn are not visible to break and continue inside it
+func (p *parser) functionBody() ([]syntaxtree.Stmt, error) {
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { before fn body")
+	}
+	p.funcDepth++
+	loops := p.loops
+	p.loops = nil
+	body, err := p.block()
+	p.loops = loops
+	p.funcDepth--
+	if err != nil {
+		return nil, err
+	}
+	return body.(syntaxtree.BlockStmt).Statements, nil
+}
+
+// lambda parses an anonymous fn(a, b) { ... }
+func (p *parser) lambda() (syntaxtree.Expr, error) {
+	keyword := p.advance()
+	params, types, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	ret, err := p.returnType()
+	if err != nil {
+		return nil, err
+	}
+	body, err := p.functionBody()
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.FunctionExpr{Keyword: keyword, Params: params, ParamTypes: types, Return: ret, Body: body}, nil
+}
+
+// isArrow looks past a parenthesized list of parameters for =>
+func (p *parser) isArrow() bool {
+	depth := 0
+	for i := p.current; ; i++ {
+		switch p.tokens[i].Type {
+		case tokenizer.LEFT_PAREN:
+			depth++
+		case tokenizer.RIGHT_PAREN:
+			depth--
+			if depth == 0 {
+				return p.tokens[i+1].Type == tokenizer.ARROW
+			}
+		case tokenizer.IDENTIFIER, tokenizer.COMMA, tokenizer.COLON, tokenizer.LEFT_BRACKET, tokenizer.RIGHT_BRACKET, tokenizer.FN, tokenizer.MINUS_GREATER:
+		default:
+			return false
+		}
+	}
+}
+
+// arrow parses (a, b) => expression, its body returns the expression
+func (p *parser) arrow() (syntaxtree.Expr, error) {
+	params, types, err := p.parameters()
+	if err != nil {
+		return nil, err
+	}
+	arrow := p.advance()
+	p.funcDepth++
+	loops := p.loops
+	p.loops = nil
+	expr, err := p.expression()
+	p.loops = loops
+	p.funcDepth--
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.FunctionExpr{Keyword: arrow, Params: params, ParamTypes: types, Body: []syntaxtree.Stmt{syntaxtree.ReturnStmt{Keyword: arrow, Value: expr}}}, nil
+}
+
+func (p *parser) structDecl() (syntaxtree.Stmt, error) {
+	p.advance()
+	name := p.advance()
+	if name.Type != tokenizer.IDENTIFIER {
+		return nil, p.generateError("bad name for struct")
+	}
+	typeParams, bounds, err := p.typeParameters()
+	if err != nil {
+		return nil, err
+	}
+	traits, err := p.conformance()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { after struct name")
+	}
+	p.advance()
+	var fields []tokenizer.Token
+	var types []syntaxtree.TypeExpr
+	for !p.isAtEnd() && !p.checkMany([]tokenizer.TokenType{tokenizer.RIGHT_BRACE, tokenizer.FN}) {
+		field := p.advance()
+		if field.Type != tokenizer.IDENTIFIER {
+			return nil, p.generateError("bad name for field")
+		}
+		for _, v := range fields {
+			if v.Content == field.Content {
+				return nil, p.generateError("duplicate field " + field.Content)
+			}
+		}
+		var typ syntaxtree.TypeExpr
+		if p.check(tokenizer.COLON) {
+			p.advance()
+			var err error
+			typ, err = p.typeExpr()
+			if err != nil {
+				return nil, err
+			}
+		}
+		fields = append(fields, field)
+		types = append(types, typ)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	var methods []syntaxtree.FuncDeclStmt
+	for p.check(tokenizer.FN) {
+		method, err := p.funcDecl()
+		if err != nil {
+			return nil, err
+		}
+		name := method.(syntaxtree.FuncDeclStmt).Name
+		for _, v := range fields {
+			if v.Content == name.Content {
+				return nil, p.generateError("method " + name.Content + " clashes with field")
+			}
+		}
+		for _, v := range methods {
+			if v.Name.Content == name.Content {
+				return nil, p.generateError("duplicate method " + name.Content)
+			}
+		}
+		methods = append(methods, dropSelf(method.(syntaxtree.FuncDeclStmt)))
+	}
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after struct body")
+	}
+	p.advance()
+	return syntaxtree.StructDeclStmt{Name: name, TypeParams: typeParams, Bounds: bounds, Fields: fields, FieldTypes: types, Traits: traits, Methods: methods}, nil
+}
+
+// dropSelf removes an explicit self from the front of the parameters of a method, self is always bound anyway
+func dropSelf(method syntaxtree.FuncDeclStmt) syntaxtree.FuncDeclStmt {
+	if len(method.Params) > 0 && method.Params[0].Content == "self" {
+		method.Params = method.Params[1:]
+		method.ParamTypes = method.ParamTypes[1:]
+	}
+	return method
+}
+
+// conformance parses the optional list of traits a struct declares it implements
+func (p *parser) conformance() ([]tokenizer.Token, error) {
+	if !p.check(tokenizer.COLON) {
+		return nil, nil
+	}
+	p.advance()
+	var traits []tokenizer.Token
+	for {
+		trait := p.advance()
+		if trait.Type != tokenizer.IDENTIFIER {
+			return nil, p.generateError("bad name for trait")
+		}
+		for _, v := range traits {
+			if v.Content == trait.Content {
+				return nil, p.generateError("duplicate trait " + trait.Content)
+			}
+		}
+		traits = append(traits, trait)
+		if !p.check(tokenizer.COMMA) {
+			return traits, nil
+		}
+		p.advance()
+	}
+}
+
+func (p *parser) traitDecl() (syntaxtree.Stmt, error) {
+	p.advance()
+	name := p.advance()
+	if name.Type != tokenizer.IDENTIFIER {
+		return nil, p.generateError("bad name for trait")
+	}
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { after trait name")
+	}
+	p.advance()
+	stmt := syntaxtree.TraitDeclStmt{Name: name}
+	for p.check(tokenizer.FN) {
+		method, err := p.funcSignature()
+		if err != nil {
+			return nil, err
+		}
+		method = dropSelf(method)
+		for _, v := range append(stmt.Methods, stmt.Defaults...) {
+			if v.Name.Content == method.Name.Content {
+				return nil, p.generateError("duplicate method " + method.Name.Content)
+			}
+		}
+		if p.check(tokenizer.LEFT_BRACE) {
+			method.Body, err = p.functionBody()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Defaults = append(stmt.Defaults, method)
+			continue
+		}
+		if p.check(tokenizer.SEMICOLON) {
+			p.advance()
+		}
+		stmt.Methods = append(stmt.Methods, method)
+	}
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after trait body")
+	}
+	p.advance()
+	return stmt, nil
+}
 
+// enumDecl parses enum Name { Variant, Variant(field, ...), ... }
+func (p *parser) enumDecl() (syntaxtree.Stmt, error) {
+	p.advance()
+	name := p.advance()
+	if name.Type != tokenizer.IDENTIFIER {
+		return nil, p.generateError("bad name for enum")
+	}
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { after enum name")
+	}
+	p.advance()
+	stmt := syntaxtree.EnumDeclStmt{Name: name}
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACE) {
+		variant := p.advance()
+		if variant.Type != tokenizer.IDENTIFIER {
+			return nil, p.generateError("bad name for variant")
+		}
+		for _, v := range stmt.Variants {
+			if v.Content == variant.Content {
+				return nil, p.generateError("duplicate variant " + variant.Content)
+			}
+		}
+		var fields []tokenizer.Token
+		var types []syntaxtree.TypeExpr
+		if p.check(tokenizer.LEFT_PAREN) {
+			var err error
+			fields, types, err = p.parameters()
+			if err != nil {
+				return nil, err
+			}
+		}
+		stmt.Variants = append(stmt.Variants, variant)
+		stmt.Fields = append(stmt.Fields, fields)
+		stmt.FieldTypes = append(stmt.FieldTypes, types)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after enum variants")
+	}
+	p.advance()
+	p.enums[name.Content] = stmt
+	return stmt, nil
 }
 
 func (p *parser) statement() (syntaxtree.Stmt, error) {
@@ -150,77 +640,476 @@ func (p *parser) statement() (syntaxtree.Stmt, error) {
 	} else if p.check(tokenizer.LEFT_BRACE) {
 		return p.block()
 	} else if p.check(tokenizer.FOR) {
-		return p.forStmt()
+		return p.forStmt(tokenizer.Token{})
+	} else if p.check(tokenizer.WHILE) {
+		return p.whileStmt(tokenizer.Token{})
+	} else if p.check(tokenizer.IDENTIFIER) && p.peekNext().Type == tokenizer.COLON {
+		return p.labeledStmt()
+	} else if p.check(tokenizer.RETURN) {
+		return p.returnStmt()
+	} else if p.check(tokenizer.BREAK) {
+		return p.breakStmt()
+	} else if p.check(tokenizer.CONTINUE) {
+		return p.continueStmt()
+	} else if p.check(tokenizer.MATCH) {
+		return p.matchStmt()
 	} else {
 		return p.exprStmt()
 	}
 }
 
-func (p *parser) forStmt() (syntaxtree.Stmt, error) {
-	p.advance()
-	if !p.check(tokenizer.LEFT_PAREN) {
-		return nil, p.generateError("expected ( after for")
-	}
-	p.advance()
-	prestmt, err := p.varDelc()
+func (p *parser) labeledStmt() (syntaxtree.Stmt, error) {
+	label := p.advance()
+	p.advance()
+	for _, v := range p.loops {
+		if v == label.Content {
+			return nil, p.generateError("label " + label.Content + " already used by enclosing loop")
+		}
+	}
+	if p.check(tokenizer.FOR) {
+		return p.forStmt(label)
+	} else if p.check(tokenizer.WHILE) {
+		return p.whileStmt(label)
+	} else {
+		return nil, p.generateError("expected loop after label")
+	}
+}
+
+// loopBody parses the body of a loop while the loop is visible to break and continue
+func (p *parser) loopBody(label tokenizer.Token) (syntaxtree.Stmt, error) {
+	p.loops = append(p.loops, label.Content)
+	block, err := p.statement()
+	p.loops = p.loops[:len(p.loops)-1]
+	return block, err
+}
+
+// loopLabel parses the optional label after break or continue
+func (p *parser) loopLabel(keyword tokenizer.Token) (tokenizer.Token, error) {
+	if len(p.loops) == 0 {
+		return tokenizer.Token{}, p.generateError(keyword.Content + " outside of loop")
+	}
+	if !p.check(tokenizer.IDENTIFIER) {
+		return tokenizer.Token{}, nil
+	}
+	label := p.advance()
+	for _, v := range p.loops {
+		if v == label.Content {
+			return label, nil
+		}
+	}
+	return tokenizer.Token{}, p.generateError("unknown loop label " + label.Content)
+}
+
+func (p *parser) breakStmt() (syntaxtree.Stmt, error) {
+	keyword := p.advance()
+	label, err := p.loopLabel(keyword)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.endStmt("expected ; after break"); err != nil {
+		return nil, err
+	}
+	return syntaxtree.BreakStmt{Keyword: keyword, Label: label}, nil
+}
+
+func (p *parser) continueStmt() (syntaxtree.Stmt, error) {
+	keyword := p.advance()
+	label, err := p.loopLabel(keyword)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.endStmt("expected ; after continue"); err != nil {
+		return nil, err
+	}
+	return syntaxtree.ContinueStmt{Keyword: keyword, Label: label}, nil
+}
+
+func (p *parser) whileStmt(label tokenizer.Token) (syntaxtree.Stmt, error) {
+	p.advance()
+	if !p.check(tokenizer.LEFT_PAREN) {
+		return nil, p.generateError("expected ( after while")
+	}
+	p.advance()
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.RIGHT_PAREN) {
+		return nil, p.generateError("expected ) after while condition")
+	}
+	p.advance()
+	block, err := p.loopBody(label)
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.WhileStmt{Label: label, Condition: cond, Block: block}, nil
+}
+
+func (p *parser) forStmt(label tokenizer.Token) (syntaxtree.Stmt, error) {
+	p.advance()
+	if !p.check(tokenizer.LEFT_PAREN) {
+		return nil, p.generateError("expected ( after for")
+	}
+	p.advance()
+	prestmt, err := p.varDelc()
+	if err != nil {
+		return nil, err
+	}
+	// if !p.check(tokenizer.SEMICOLON) {
+	// 	return nil, p.generateError("expected ; after PreStatement")
+	// }
+	// p.advance()
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.SEMICOLON) {
+		return nil, p.generateError("expected ; after condition")
+	}
+	p.advance()
+	poststmt, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	poststmt, err = p.increment(poststmt)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.check(tokenizer.RIGHT_PAREN) {
+		return nil, p.generateError("expected ) after for block")
+	}
+	p.advance()
+
+	block, err := p.loopBody(label)
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.ForStmt{Label: label, PreStatement: prestmt, Condition: cond, PostStatement: poststmt, Block: block}, nil
+}
+
+func (p *parser) returnStmt() (syntaxtree.Stmt, error) {
+	keyword := p.advance()
+	if p.funcDepth == 0 {
+		return nil, p.generateError("return outside of fn")
+	}
+	var value syntaxtree.Expr
+	if !p.atStmtEnd() {
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		value = expr
+	}
+	if err := p.endStmt("expected ; after return"); err != nil {
+		return nil, err
+	}
+	return syntaxtree.ReturnStmt{Keyword: keyword, Value: value}, nil
+}
+
+func (p *parser) ifStmt() (syntaxtree.Stmt, error) {
+	p.advance()
+	if !p.check(tokenizer.LEFT_PAREN) {
+		return nil, p.generateError("expected ( after if")
+	}
+	p.advance()
+	expr, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.RIGHT_PAREN) {
+		return nil, p.generateError("expected ) after if statement")
+	}
+	p.advance()
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { after if statement")
+	}
+	block, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.ELSE) {
+		return syntaxtree.IfStmt{Condition: expr, Block: block}, nil
+	}
+	p.advance()
+	var elseBlock syntaxtree.Stmt
+	if p.check(tokenizer.IF) {
+		elseBlock, err = p.ifStmt()
+	} else if p.check(tokenizer.LEFT_BRACE) {
+		elseBlock, err = p.statement()
+	} else {
+		return nil, p.generateError("expected { or if after else")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.IfStmt{Condition: expr, Block: block, Else: elseBlock}, nil
+}
+
+// matchStmt parses match (value) { pattern (if guard)? => statement ,? ... }
+func (p *parser) matchStmt() (syntaxtree.Stmt, error) {
+	keyword := p.advance()
+	if !p.check(tokenizer.LEFT_PAREN) {
+		return nil, p.generateError("expected ( after match")
+	}
+	p.advance()
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.RIGHT_PAREN) {
+		return nil, p.generateError("expected ) after match value")
+	}
+	p.advance()
+	if !p.check(tokenizer.LEFT_BRACE) {
+		return nil, p.generateError("expected { before match arms")
+	}
+	p.advance()
+	stmt := syntaxtree.MatchStmt{Keyword: keyword, Value: value}
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACE) {
+		pattern, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		var guard syntaxtree.Expr
+		if p.check(tokenizer.IF) {
+			p.advance()
+			guard, err = p.expression()
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !p.check(tokenizer.ARROW) {
+			return nil, p.generateError("expected => after pattern")
+		}
+		p.advance()
+		body, err := p.armBody()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Patterns = append(stmt.Patterns, pattern)
+		stmt.Guards = append(stmt.Guards, guard)
+		stmt.Bodies = append(stmt.Bodies, body)
+		if p.check(tokenizer.COMMA) {
+			p.advance()
+		}
+	}
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after match arms")
+	}
+	p.advance()
+	if len(stmt.Patterns) == 0 {
+		return nil, p.generateError("match without arms")
+	}
+	p.exhaustive(stmt)
+	return stmt, nil
+}
+
+func (p *parser) armBody() (syntaxtree.Stmt, error) {
+	inArm := p.inArm
+	p.inArm = true
+	body, err := p.statement()
+	p.inArm = inArm
+	return body, err
+}
+
+// atStmtEnd reports whether the next token ends a simple statement
+func (p *parser) atStmtEnd() bool {
+	return p.check(tokenizer.SEMICOLON) || p.inArm && p.checkMany([]tokenizer.TokenType{tokenizer.COMMA, tokenizer.RIGHT_BRACE})
+}
+
+// endStmt consumes the ; ending a simple statement, the end of a match arm is left for matchStmt
+func (p *parser) endStmt(msg string) error {
+	if !p.atStmtEnd() {
+		return p.generateError(msg)
+	}
+	if p.check(tokenizer.SEMICOLON) {
+		p.advance()
+	}
+	return nil
+}
+
+// pattern parses one or more patterns separated by |
+func (p *parser) pattern() (syntaxtree.Pattern, error) {
+	first, err := p.singlePattern()
 	if err != nil {
 		return nil, err
 	}
-	// if !p.check(tokenizer.SEMICOLON) {
-	// 	return nil, p.generateError("expected ; after PreStatement")
-	// }
-	// p.advance()
-	cond, err := p.expression()
-	if err != nil {
-		return nil, err
+	if !p.check(tokenizer.PIPE) {
+		return first, nil
 	}
-	if !p.check(tokenizer.SEMICOLON) {
-		return nil, p.generateError("expected ; after condition")
+	names := make(map[string]bool)
+	bound(first, names)
+	alternatives := []syntaxtree.Pattern{first}
+	for p.check(tokenizer.PIPE) {
+		p.advance()
+		next, err := p.singlePattern()
+		if err != nil {
+			return nil, err
+		}
+		nextNames := make(map[string]bool)
+		bound(next, nextNames)
+		if !maps.Equal(names, nextNames) {
+			return nil, p.generateError("alternatives of a pattern must bind the same names")
+		}
+		alternatives = append(alternatives, next)
 	}
-	p.advance()
-	poststmt, err := p.expression()
-	if err != nil {
-		return nil, err
+	return syntaxtree.AlternativePattern{Alternatives: alternatives}, nil
+}
+
+// bound collects the names a pattern binds, every alternative binds the same names so the first one is enough
+func bound(pattern syntaxtree.Pattern, names map[string]bool) {
+	switch pattern := pattern.(type) {
+	case syntaxtree.BindingPattern:
+		names[pattern.Name.Content] = true
+	case syntaxtree.ListPattern:
+		for _, v := range pattern.Elements {
+			bound(v, names)
+		}
+	case syntaxtree.EnumPattern:
+		for _, v := range pattern.Patterns {
+			bound(v, names)
+		}
+	case syntaxtree.StructPattern:
+		for _, v := range pattern.Patterns {
+			bound(v, names)
+		}
+	case syntaxtree.AlternativePattern:
+		bound(pattern.Alternatives[0], names)
 	}
+}
 
-	if !p.check(tokenizer.RIGHT_PAREN) {
-		return nil, p.generateError("expected ) after for block")
+func (p *parser) singlePattern() (syntaxtree.Pattern, error) {
+	if p.checkMany([]tokenizer.TokenType{tokenizer.NUMBER, tokenizer.STRING, tokenizer.TRUE, tokenizer.FALSE, tokenizer.NIL}) {
+		return syntaxtree.LiteralPattern{Value: syntaxtree.LiteralExpr{Value: p.advance()}}, nil
+	} else if p.check(tokenizer.MINUS) && p.peekNext().Type == tokenizer.NUMBER {
+		minus := p.advance()
+		return syntaxtree.LiteralPattern{Value: syntaxtree.UnaryExpr{Operator: minus, Right: syntaxtree.LiteralExpr{Value: p.advance()}}}, nil
+	} else if p.check(tokenizer.IDENTIFIER) && p.peek().Content == "_" {
+		return syntaxtree.WildcardPattern{Underscore: p.advance()}, nil
+	} else if p.check(tokenizer.IDENTIFIER) && p.peekNext().Type == tokenizer.DOT {
+		return p.enumPattern()
+	} else if p.check(tokenizer.IDENTIFIER) && p.peekNext().Type == tokenizer.LEFT_BRACE {
+		return p.structPattern()
+	} else if p.check(tokenizer.IDENTIFIER) {
+		return syntaxtree.BindingPattern{Name: p.advance()}, nil
+	} else if p.check(tokenizer.LEFT_BRACKET) {
+		return p.listPattern()
+	} else {
+		return nil, p.generateError("expected pattern")
 	}
-	p.advance()
+}
 
-	block, err := p.statement()
-	if err != nil {
-		return nil, err
+// listPattern parses [pattern, ...], it only matches lists of the same length
+func (p *parser) listPattern() (syntaxtree.Pattern, error) {
+	bracket := p.advance()
+	var elements []syntaxtree.Pattern
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACKET) {
+		element, err := p.pattern()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
 	}
-	return syntaxtree.ForStmt{PreStatement: prestmt, Condition: cond, PostStatement: poststmt, Block: block}, nil
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		return nil, p.generateError("expected ] after list pattern")
+	}
+	p.advance()
+	return syntaxtree.ListPattern{Bracket: bracket, Elements: elements}, nil
 }
 
-func (p *parser) ifStmt() (syntaxtree.Stmt, error) {
+// enumPattern parses Enum.Variant(pattern, ...), without parentheses the payload is not checked
+func (p *parser) enumPattern() (syntaxtree.Pattern, error) {
+	enum := p.advance()
 	p.advance()
-	if !p.check(tokenizer.LEFT_PAREN) {
-		return nil, p.generateError("expected ( after if")
+	variant := p.advance()
+	if variant.Type != tokenizer.IDENTIFIER {
+		return nil, p.generateError("bad variant name in pattern")
 	}
-	p.advance()
-	expr, err := p.expression()
-	if err != nil {
-		return nil, err
+	pattern := syntaxtree.EnumPattern{Enum: enum, Variant: variant}
+	hasPayload := p.check(tokenizer.LEFT_PAREN)
+	if hasPayload {
+		p.advance()
+		pattern.Patterns = []syntaxtree.Pattern{}
+		for !p.isAtEnd() && !p.check(tokenizer.RIGHT_PAREN) {
+			sub, err := p.pattern()
+			if err != nil {
+				return nil, err
+			}
+			pattern.Patterns = append(pattern.Patterns, sub)
+			if !p.check(tokenizer.COMMA) {
+				break
+			}
+			p.advance()
+		}
+		if !p.check(tokenizer.RIGHT_PAREN) {
+			return nil, p.generateError("expected ) after variant patterns")
+		}
+		p.advance()
 	}
-	if !p.check(tokenizer.RIGHT_PAREN) {
-		return nil, p.generateError("expected ) after if statement")
+	if decl, ok := p.enums[enum.Content]; ok {
+		fields, ok := variantFields(decl, variant.Content)
+		if !ok {
+			return nil, p.generateError(enum.Content + " has no variant " + variant.Content)
+		}
+		if hasPayload && len(fields) != len(pattern.Patterns) {
+			return nil, p.generateError(enum.Content + "." + variant.Content + " has " + strconv.Itoa(len(fields)) + " fields but the pattern has " + strconv.Itoa(len(pattern.Patterns)))
+		}
+	}
+	return pattern, nil
+}
+
+func variantFields(decl syntaxtree.EnumDeclStmt, variant string) ([]tokenizer.Token, bool) {
+	for i, v := range decl.Variants {
+		if v.Content == variant {
+			return decl.Fields[i], true
+		}
 	}
+	return nil, false
+}
+
+// structPattern parses Name{field, field: pattern}, a bare field binds its value to the field name
+func (p *parser) structPattern() (syntaxtree.Pattern, error) {
+	name := p.advance()
 	p.advance()
-	if !p.check(tokenizer.LEFT_BRACE) {
-		return nil, p.generateError("expected { after if statement")
+	pattern := syntaxtree.StructPattern{Name: name}
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACE) {
+		field := p.advance()
+		if field.Type != tokenizer.IDENTIFIER {
+			return nil, p.generateError("bad field name in pattern")
+		}
+		var sub syntaxtree.Pattern = syntaxtree.BindingPattern{Name: field}
+		if p.check(tokenizer.COLON) {
+			p.advance()
+			var err error
+			sub, err = p.pattern()
+			if err != nil {
+				return nil, err
+			}
+		}
+		pattern.Fields = append(pattern.Fields, field)
+		pattern.Patterns = append(pattern.Patterns, sub)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
 	}
-	block, err := p.statement()
-	if err != nil {
-		return nil, err
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after field patterns")
 	}
-	return syntaxtree.IfStmt{Condition: expr, Block: block}, nil
+	p.advance()
+	return pattern, nil
 }
 
 func (p *parser) block() (syntaxtree.Stmt, error) {
 	p.advance()
+	inArm := p.inArm
+	p.inArm = false
+	defer func() { p.inArm = inArm }()
 	var stmt []syntaxtree.Stmt
 	for !p.check(tokenizer.RIGHT_BRACE) {
 		a, err := p.declaration()
@@ -238,10 +1127,13 @@ func (p *parser) exprStmt() (syntaxtree.Stmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	if !p.check(tokenizer.SEMICOLON) {
-		return nil, p.generateError("expected ;")
+	expr, err = p.increment(expr)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.endStmt("expected ;"); err != nil {
+		return nil, err
 	}
-	p.advance()
 	return syntaxtree.ExpressionStmt{Expression: expr}, nil
 }
 
@@ -251,10 +1143,9 @@ func (p *parser) printStmt() (syntaxtree.Stmt, error) {
 	if err != nil {
 		return nil, err
 	}
-	if !p.check(tokenizer.SEMICOLON) {
-		return nil, p.generateError("expected ;")
+	if err := p.endStmt("expected ;"); err != nil {
+		return nil, err
 	}
-	p.advance()
 	return syntaxtree.PrintStmt{Expression: expr}, nil
 }
 
@@ -262,31 +1153,147 @@ func (p *parser) expression() (syntaxtree.Expr, error) {
 	return p.assignment()
 }
 
+var assignmentOperators = []tokenizer.TokenType{
+	tokenizer.EQUAL, tokenizer.PLUS_EQUAL, tokenizer.MINUS_EQUAL, tokenizer.STAR_EQUAL, tokenizer.SLASH_EQUAL, tokenizer.PERCENT_EQUAL,
+}
+
 func (p *parser) assignment() (syntaxtree.Expr, error) {
-	name, err := p.bitwise()
+	name, err := p.conditional()
 	if err != nil {
 		return nil, err
 	}
-	if !p.check(tokenizer.EQUAL) {
+	if !p.checkMany(assignmentOperators) {
 		return name, nil
 	}
-	if val, ok := name.(syntaxtree.LiteralExpr); !ok || val.Value.Type != tokenizer.IDENTIFIER {
-		return nil, p.generateError("expected name")
-	}
 	op := p.advance()
 	expr, err := p.expression()
 	if err != nil {
 		return nil, err
 	}
-	return syntaxtree.BinaryExpr{Left: name, Operator: op, Right: expr}, nil
+	return p.assign(name, op, expr)
+}
+
+// assign builds the node storing value into target, op is = or the compound operator applied on the way
+func (p *parser) assign(target syntaxtree.Expr, op tokenizer.Token, value syntaxtree.Expr) (syntaxtree.Expr, error) {
+	switch val := target.(type) {
+	case syntaxtree.GetExpr:
+		return syntaxtree.SetExpr{Object: val.Object, Name: val.Name, Operator: op, Value: value}, nil
+	case syntaxtree.IndexExpr:
+		return syntaxtree.IndexSetExpr{Object: val.Object, Bracket: val.Bracket, Index: val.Index, Operator: op, Value: value}, nil
+	case syntaxtree.LiteralExpr:
+		if val.Value.Type == tokenizer.IDENTIFIER {
+			return syntaxtree.BinaryExpr{Left: target, Operator: op, Right: value}, nil
+		}
+	}
+	return nil, p.generateError("expected name")
+}
+
+// increment turns x++ and x-- into compound assignments, they are only allowed as statements
+func (p *parser) increment(expr syntaxtree.Expr) (syntaxtree.Expr, error) {
+	if !p.checkMany([]tokenizer.TokenType{tokenizer.PLUS_PLUS, tokenizer.MINUS_MINUS}) {
+		return expr, nil
+	}
+	op := p.advance()
+	one := syntaxtree.LiteralExpr{Value: tokenizer.Token{Type: tokenizer.NUMBER, Content: "1", Len: 1, Line: op.Line}}
+	return p.assign(expr, op, one)
+}
+
+func (p *parser) conditional() (syntaxtree.Expr, error) {
+	cond, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.QUESTION) {
+		return cond, nil
+	}
+	question := p.advance()
+	then, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.check(tokenizer.COLON) {
+		return nil, p.generateError("expected : in conditional expression")
+	}
+	p.advance()
+	otherwise, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.ConditionalExpr{Condition: cond, Question: question, Then: then, Else: otherwise}, nil
 }
 
-func (p *parser) bitwise() (syntaxtree.Expr, error) {
+func (p *parser) or() (syntaxtree.Expr, error) {
+	expr, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokenizer.OR) {
+		token := p.advance()
+		next, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		expr = syntaxtree.Expr(syntaxtree.LogicalExpr{Left: expr, Operator: token, Right: next})
+	}
+	return expr, nil
+}
+
+func (p *parser) and() (syntaxtree.Expr, error) {
+	expr, err := p.bitOr()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokenizer.AND) {
+		token := p.advance()
+		next, err := p.bitOr()
+		if err != nil {
+			return nil, err
+		}
+		expr = syntaxtree.Expr(syntaxtree.LogicalExpr{Left: expr, Operator: token, Right: next})
+	}
+	return expr, nil
+}
+
+func (p *parser) bitOr() (syntaxtree.Expr, error) {
+	expr, err := p.bitXor()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokenizer.PIPE) {
+		token := p.peek()
+		p.advance()
+		next, err := p.bitXor()
+		if err != nil {
+			return nil, err
+		}
+		expr = syntaxtree.Expr(syntaxtree.BinaryExpr{Left: syntaxtree.Expr(expr), Operator: token, Right: next})
+	}
+	return expr, nil
+}
+
+func (p *parser) bitXor() (syntaxtree.Expr, error) {
+	expr, err := p.bitAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.check(tokenizer.CARET) {
+		token := p.peek()
+		p.advance()
+		next, err := p.bitAnd()
+		if err != nil {
+			return nil, err
+		}
+		expr = syntaxtree.Expr(syntaxtree.BinaryExpr{Left: syntaxtree.Expr(expr), Operator: token, Right: next})
+	}
+	return expr, nil
+}
+
+func (p *parser) bitAnd() (syntaxtree.Expr, error) {
 	expr, err := p.equality()
 	if err != nil {
 		return nil, err
 	}
-	for p.checkMany([]tokenizer.TokenType{tokenizer.AMPERSAND, tokenizer.PIPE}) {
+	for p.check(tokenizer.AMPERSAND) {
 		token := p.peek()
 		p.advance()
 		next, err := p.equality()
@@ -316,11 +1323,28 @@ func (p *parser) equality() (syntaxtree.Expr, error) {
 }
 
 func (p *parser) comparison() (syntaxtree.Expr, error) {
+	expr, err := p.shift()
+	if err != nil {
+		return nil, err
+	}
+	for p.checkMany([]tokenizer.TokenType{tokenizer.LESS, tokenizer.LESS_EQUAL, tokenizer.GREATER, tokenizer.GREATER_EQUAL, tokenizer.IN}) {
+		token := p.peek()
+		p.advance()
+		next, err := p.shift()
+		if err != nil {
+			return nil, err
+		}
+		expr = syntaxtree.Expr(syntaxtree.BinaryExpr{Left: syntaxtree.Expr(expr), Operator: token, Right: next})
+	}
+	return expr, nil
+}
+
+func (p *parser) shift() (syntaxtree.Expr, error) {
 	expr, err := p.term()
 	if err != nil {
 		return nil, err
 	}
-	for p.checkMany([]tokenizer.TokenType{tokenizer.LESS, tokenizer.LESS_EQUAL, tokenizer.GREATER, tokenizer.GREATER_EQUAL}) {
+	for p.checkMany([]tokenizer.TokenType{tokenizer.LESS_LESS, tokenizer.GREATER_GREATER}) {
 		token := p.peek()
 		p.advance()
 		next, err := p.term()
@@ -354,7 +1378,7 @@ func (p *parser) factor() (syntaxtree.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	for p.checkMany([]tokenizer.TokenType{tokenizer.SLASH, tokenizer.STAR}) {
+	for p.checkMany([]tokenizer.TokenType{tokenizer.SLASH, tokenizer.STAR, tokenizer.PERCENT}) {
 		token := p.peek()
 		p.advance()
 		next, err := p.unary()
@@ -367,7 +1391,7 @@ func (p *parser) factor() (syntaxtree.Expr, error) {
 }
 
 func (p *parser) unary() (syntaxtree.Expr, error) {
-	if p.checkMany([]tokenizer.TokenType{tokenizer.EXCLAMATION, tokenizer.MINUS}) {
+	if p.checkMany([]tokenizer.TokenType{tokenizer.EXCLAMATION, tokenizer.MINUS, tokenizer.TILDE}) {
 		token := p.peek()
 		p.advance()
 		next, err := p.unary()
@@ -376,8 +1400,25 @@ func (p *parser) unary() (syntaxtree.Expr, error) {
 		}
 		return syntaxtree.Expr(syntaxtree.UnaryExpr{Operator: token, Right: next}), nil
 	} else {
-		return p.call()
+		return p.power()
+	}
+}
+
+// power is right associative and binds tighter than a unary operator on its left: -2 ** 2 == -4
+func (p *parser) power() (syntaxtree.Expr, error) {
+	expr, err := p.call()
+	if err != nil {
+		return nil, err
 	}
+	if !p.check(tokenizer.STAR_STAR) {
+		return expr, nil
+	}
+	token := p.advance()
+	next, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return syntaxtree.BinaryExpr{Left: expr, Operator: token, Right: next}, nil
 }
 
 func (p *parser) call() (syntaxtree.Expr, error) {
@@ -385,7 +1426,23 @@ func (p *parser) call() (syntaxtree.Expr, error) {
 	if err != nil {
 		return nil, err
 	}
-	for p.check(tokenizer.LEFT_PAREN) {
+	for p.checkMany([]tokenizer.TokenType{tokenizer.LEFT_PAREN, tokenizer.DOT, tokenizer.LEFT_BRACKET}) {
+		if p.check(tokenizer.LEFT_BRACKET) {
+			expr, err = p.index(expr)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if p.check(tokenizer.DOT) {
+			p.advance()
+			name := p.advance()
+			if name.Type != tokenizer.IDENTIFIER {
+				return nil, p.generateError("expected field name after .")
+			}
+			expr = syntaxtree.GetExpr{Object: expr, Name: name}
+			continue
+		}
 		var args []syntaxtree.Expr
 		p.advance()
 		for !p.isAtEnd() && !p.check(tokenizer.RIGHT_PAREN) {
@@ -409,9 +1466,119 @@ func (p *parser) call() (syntaxtree.Expr, error) {
 	return expr, nil
 }
 
+func (p *parser) index(object syntaxtree.Expr) (syntaxtree.Expr, error) {
+	bracket := p.advance()
+	var start, end syntaxtree.Expr
+	var err error
+	if !p.check(tokenizer.COLON) {
+		start, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.check(tokenizer.RIGHT_BRACKET) {
+			p.advance()
+			return syntaxtree.IndexExpr{Object: object, Bracket: bracket, Index: start}, nil
+		}
+	}
+	if !p.check(tokenizer.COLON) {
+		return nil, p.generateError("expected ] or : after index")
+	}
+	p.advance()
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		end, err = p.expression()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		return nil, p.generateError("expected ] after slice")
+	}
+	p.advance()
+	return syntaxtree.SliceExpr{Object: object, Bracket: bracket, Start: start, End: end}, nil
+}
+
+func (p *parser) list() (syntaxtree.Expr, error) {
+	bracket := p.advance()
+	var elements []syntaxtree.Expr
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACKET) {
+		element, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_BRACKET) {
+		return nil, p.generateError("expected ] after list elements")
+	}
+	p.advance()
+	return syntaxtree.ListExpr{Bracket: bracket, Elements: elements}, nil
+}
+
+// dict parses a map literal, in expression position { never starts a block
+func (p *parser) dict() (syntaxtree.Expr, error) {
+	brace := p.advance()
+	var keys, values []syntaxtree.Expr
+	for !p.isAtEnd() && !p.check(tokenizer.RIGHT_BRACE) {
+		key, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if !p.check(tokenizer.COLON) {
+			return nil, p.generateError("expected : after map key")
+		}
+		p.advance()
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		values = append(values, value)
+		if !p.check(tokenizer.COMMA) {
+			break
+		}
+		p.advance()
+	}
+	if !p.check(tokenizer.RIGHT_BRACE) {
+		return nil, p.generateError("expected } after map entries")
+	}
+	p.advance()
+	return syntaxtree.MapExpr{Brace: brace, Keys: keys, Values: values}, nil
+}
+
+// interpolation parses the parts of an interpolated string, literal text becomes STRING literals
+func (p *parser) interpolation() (syntaxtree.Expr, error) {
+	var parts []syntaxtree.Expr
+	for p.check(tokenizer.INTERPOLATION) {
+		text := p.advance()
+		if text.Content != "" {
+			parts = append(parts, syntaxtree.LiteralExpr{Value: tokenizer.Token{Type: tokenizer.STRING, Content: text.Content, Len: text.Len, Line: text.Line}})
+		}
+		expr, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+	}
+	if !p.check(tokenizer.STRING) {
+		return nil, p.generateError("expected } after interpolated expression")
+	}
+	if text := p.advance(); text.Content != "" {
+		parts = append(parts, syntaxtree.LiteralExpr{Value: text})
+	}
+	return syntaxtree.InterpolatedStringExpr{Parts: parts}, nil
+}
+
 func (p *parser) primary() (syntaxtree.Expr, error) {
-	if p.checkMany([]tokenizer.TokenType{tokenizer.NUMBER, tokenizer.STRING, tokenizer.IDENTIFIER}) {
+	if p.checkMany([]tokenizer.TokenType{tokenizer.NUMBER, tokenizer.STRING, tokenizer.IDENTIFIER, tokenizer.TRUE, tokenizer.FALSE, tokenizer.NIL}) {
 		return syntaxtree.Expr(syntaxtree.LiteralExpr{Value: p.advance()}), nil
+	} else if p.check(tokenizer.FN) {
+		return p.lambda()
+	} else if p.check(tokenizer.LEFT_PAREN) && p.isArrow() {
+		return p.arrow()
 	} else if p.check(tokenizer.LEFT_PAREN) {
 		p.advance()
 		expr, err := p.expression()
@@ -420,6 +1587,12 @@ func (p *parser) primary() (syntaxtree.Expr, error) {
 		}
 		p.advance()
 		return syntaxtree.Expr(syntaxtree.GroupingExpr{Inside: expr}), nil
+	} else if p.check(tokenizer.INTERPOLATION) {
+		return p.interpolation()
+	} else if p.check(tokenizer.LEFT_BRACKET) {
+		return p.list()
+	} else if p.check(tokenizer.LEFT_BRACE) {
+		return p.dict()
 	} else {
 		return nil, p.generateError("unexpected end")
 	}
@@ -0,0 +1,205 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+func parse(t *testing.T, source string) ([]syntaxtree.Stmt, error) {
+	tokens, err := tokenizer.Tokenize([]byte(source))
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	return Parse(tokens)
+}
+
+func TestReturnOutsideFn(t *testing.T) {
+	if _, err := parse(t, "fn f() { return 1; }"); err != nil {
+		t.Errorf("return in fn: %v", err)
+	}
+	if _, err := parse(t, "return 1;"); err == nil {
+		t.Error("return outside of fn was accepted")
+	}
+}
+
+func TestLoopLabels(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"while (1 == 1) { break; }", true},
+		{"outer: for (var i = 0; i < 3; i = i + 1) { while (1 == 1) { continue outer; } }", true},
+		{"break;", false},
+		{"fn f() { continue; }", false},
+		{"while (1 == 1) { break nope; }", false},
+		{"a: while (1 == 1) { a: while (1 == 1) {} }", false},
+		{"a: print 1;", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+func TestStructDecl(t *testing.T) {
+	if _, err := parse(t, "struct P { x, y } struct Empty {}"); err != nil {
+		t.Error(err)
+	}
+	if _, err := parse(t, "struct P { x, x }"); err == nil {
+		t.Error("duplicate field was accepted")
+	}
+	if _, err := parse(t, "struct P { x, y fn sum() { return self.x + self.y; } }"); err != nil {
+		t.Error(err)
+	}
+	if _, err := parse(t, "struct P { x fn x() {} }"); err == nil {
+		t.Error("method named like a field was accepted")
+	}
+}
+
+func TestConditional(t *testing.T) {
+	if _, err := parse(t, "var x = true ? 1 : false ? 2 : 3;"); err != nil {
+		t.Error(err)
+	}
+	if _, err := parse(t, "var x = true ? 1;"); err == nil {
+		t.Error("conditional without : was accepted")
+	}
+}
+
+func TestLambdas(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"var f = (a, b) => a + b;", true},
+		{"var f = () => nil;", true},
+		{"var f = fn(a) { return a; };", true},
+		{"var f = (a + 1) => a;", false},
+		{"var f = fn() { return; }; return 1;", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+func TestMatchSyntax(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"match (1) { 1 | 2 => print 1, [a, _] if a > 0 => print a, _ => {} }", true},
+		{"match (1) { 1 => print 1; }", true},
+		{"match (1) { }", false},
+		{"match (1) { 1 print 1 }", false},
+		{"match (1) { P { x: } => print 1 }", false},
+		{"enum Shape { Circle(r), Empty } match (Shape.Empty) { Shape.Circle(r) => print r, Shape.Empty => print 0 }", true},
+		{"enum Shape { Circle(r) } match (1) { Shape.Square => print 1 }", false},
+		{"enum Shape { Circle(r) } match (1) { Shape.Circle(a, b) => print 1 }", false},
+		{"enum Shape { Circle, Circle }", false},
+		{"fn f(x) { match (x) { [a] | b => print a, _ => print 0 } }", false},
+		{"match (1) { [a, _] | [_, a] | a => print a }", true},
+		{"match (1) { [1, a] | [a, 2] | [a] => print a, P { x, y: 1 } | P { y: 2, x } => print x }", true},
+		{"match (1) { P { x } | P { y } => print 1 }", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"var x: List[Int] = [];", true},
+		{"var m: Map[String, List[Int]] = {};", true},
+		{"fn f(a: Int, b) -> Int { return a; }", true},
+		{"var f: fn(Int, String) -> Bool = nil;", true},
+		{"var f = (x: Int) => x;", true},
+		{"struct P { x: Int, y } enum E { A(v: Float) }", true},
+		{"var x: = 1;", false},
+		{"var x: List[Int = [];", false},
+		{"fn f(a: ) {}", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+func TestTypeParameters(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"fn f[T, U](a: T) -> U { return nil; }", true},
+		{"struct Box[T] { value: T }", true},
+		{"var b: Box[List[Int]] = nil;", true},
+		{"fn f[T, T]() {}", false},
+		{"fn f[]() {}", false},
+		{"struct Box[T { }", false},
+		{"fn f[T: Display, U: Eq + Ord](a: T, b: U) {}", true},
+		{"struct Box[T: Display] : Display { value: T }", true},
+		{"fn f[T: ]() {}", false},
+		{"fn f[T: Eq +]() {}", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+func TestTraits(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"trait Show { fn show(self) -> String; fn twice() -> String { return self.show() + self.show(); } }", true},
+		{"trait Show { fn show(); fn show(); }", false},
+		{"trait Show { var x; }", false},
+		{"struct P : Show, Eq { fn show(self) { return \"p\"; } }", true},
+		{"struct P : Show, Show { }", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
+
+// TestExplicitSelf checks that a method naming self as its first parameter takes one argument less
+func TestExplicitSelf(t *testing.T) {
+	tree, err := parse(t, "struct P { fn add(self, n) { return n; } }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := tree[0].(syntaxtree.StructDeclStmt).Methods[0]
+	if len(method.Params) != 1 || method.Params[0].Content != "n" {
+		t.Errorf("got params %v", method.Params)
+	}
+}
+
+func TestBindings(t *testing.T) {
+	tests := []struct {
+		source string
+		ok     bool
+	}{
+		{"const N = 1; let s = \"a\";", true},
+		{"const N: Int = 1;", true},
+		{"const N;", false},
+		{"let x;", false},
+	}
+	for _, test := range tests {
+		if _, err := parse(t, test.source); (err == nil) != test.ok {
+			t.Errorf("%s: got error %v", test.source, err)
+		}
+	}
+}
@@ -10,6 +10,17 @@ type BinaryExpr struct {
 	Operator tokenizer.Token
 	Right    Expr
 }
+type ConditionalExpr struct {
+	Condition Expr
+	Question  tokenizer.Token
+	Then      Expr
+	Else      Expr
+}
+type LogicalExpr struct {
+	Left     Expr
+	Operator tokenizer.Token
+	Right    Expr
+}
 type UnaryExpr struct {
 	Operator tokenizer.Token
 	Right    Expr
@@ -25,18 +36,80 @@ type CallExpr struct {
 type LiteralExpr struct {
 	Value tokenizer.Token
 }
+type InterpolatedStringExpr struct {
+	Parts []Expr
+}
+type FunctionExpr struct {
+	Keyword    tokenizer.Token
+	Params     []tokenizer.Token
+	ParamTypes []TypeExpr
+	Return     TypeExpr
+	Body       []Stmt
+}
+type GetExpr struct {
+	Object Expr
+	Name   tokenizer.Token
+}
+type SetExpr struct {
+	Object   Expr
+	Name     tokenizer.Token
+	Operator tokenizer.Token
+	Value    Expr
+}
+type ListExpr struct {
+	Bracket  tokenizer.Token
+	Elements []Expr
+}
+type MapExpr struct {
+	Brace  tokenizer.Token
+	Keys   []Expr
+	Values []Expr
+}
+type IndexExpr struct {
+	Object  Expr
+	Bracket tokenizer.Token
+	Index   Expr
+}
+type IndexSetExpr struct {
+	Object   Expr
+	Bracket  tokenizer.Token
+	Index    Expr
+	Operator tokenizer.Token
+	Value    Expr
+}
+type SliceExpr struct {
+	Object  Expr
+	Bracket tokenizer.Token
+	Start   Expr
+	End     Expr
+}
 type ExprVisitor[E any] interface {
 	VisitBinaryExpr(expr BinaryExpr) E
+	VisitConditionalExpr(expr ConditionalExpr) E
+	VisitLogicalExpr(expr LogicalExpr) E
 	VisitUnaryExpr(expr UnaryExpr) E
 	VisitGroupingExpr(expr GroupingExpr) E
 	VisitCallExpr(expr CallExpr) E
 	VisitLiteralExpr(expr LiteralExpr) E
+	VisitInterpolatedStringExpr(expr InterpolatedStringExpr) E
+	VisitFunctionExpr(expr FunctionExpr) E
+	VisitGetExpr(expr GetExpr) E
+	VisitSetExpr(expr SetExpr) E
+	VisitListExpr(expr ListExpr) E
+	VisitMapExpr(expr MapExpr) E
+	VisitIndexExpr(expr IndexExpr) E
+	VisitIndexSetExpr(expr IndexSetExpr) E
+	VisitSliceExpr(expr SliceExpr) E
 }
 
 func AcceptExpr[E any](visitor ExprVisitor[E], expr Expr) E {
 	switch val := expr.(type) {
 	case BinaryExpr:
 		return visitor.VisitBinaryExpr(val)
+	case ConditionalExpr:
+		return visitor.VisitConditionalExpr(val)
+	case LogicalExpr:
+		return visitor.VisitLogicalExpr(val)
 	case UnaryExpr:
 		return visitor.VisitUnaryExpr(val)
 	case GroupingExpr:
@@ -45,6 +118,24 @@ func AcceptExpr[E any](visitor ExprVisitor[E], expr Expr) E {
 		return visitor.VisitCallExpr(val)
 	case LiteralExpr:
 		return visitor.VisitLiteralExpr(val)
+	case InterpolatedStringExpr:
+		return visitor.VisitInterpolatedStringExpr(val)
+	case FunctionExpr:
+		return visitor.VisitFunctionExpr(val)
+	case GetExpr:
+		return visitor.VisitGetExpr(val)
+	case SetExpr:
+		return visitor.VisitSetExpr(val)
+	case ListExpr:
+		return visitor.VisitListExpr(val)
+	case MapExpr:
+		return visitor.VisitMapExpr(val)
+	case IndexExpr:
+		return visitor.VisitIndexExpr(val)
+	case IndexSetExpr:
+		return visitor.VisitIndexSetExpr(val)
+	case SliceExpr:
+		return visitor.VisitSliceExpr(val)
 	}
 	return *new(E)
 }
@@ -0,0 +1,62 @@
+package syntaxtree
+
+import (
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+type Pattern interface{}
+type LiteralPattern struct {
+	Value Expr
+}
+type WildcardPattern struct {
+	Underscore tokenizer.Token
+}
+type BindingPattern struct {
+	Name tokenizer.Token
+}
+type AlternativePattern struct {
+	Alternatives []Pattern
+}
+type ListPattern struct {
+	Bracket  tokenizer.Token
+	Elements []Pattern
+}
+type StructPattern struct {
+	Name     tokenizer.Token
+	Fields   []tokenizer.Token
+	Patterns []Pattern
+}
+type EnumPattern struct {
+	Enum     tokenizer.Token
+	Variant  tokenizer.Token
+	Patterns []Pattern
+}
+type PatternVisitor[E any] interface {
+	VisitLiteralPattern(pattern LiteralPattern) E
+	VisitWildcardPattern(pattern WildcardPattern) E
+	VisitBindingPattern(pattern BindingPattern) E
+	VisitAlternativePattern(pattern AlternativePattern) E
+	VisitListPattern(pattern ListPattern) E
+	VisitStructPattern(pattern StructPattern) E
+	VisitEnumPattern(pattern EnumPattern) E
+}
+
+func AcceptPattern[E any](visitor PatternVisitor[E], pattern Pattern) E {
+	switch val := pattern.(type) {
+	case LiteralPattern:
+		return visitor.VisitLiteralPattern(val)
+	case WildcardPattern:
+		return visitor.VisitWildcardPattern(val)
+	case BindingPattern:
+		return visitor.VisitBindingPattern(val)
+	case AlternativePattern:
+		return visitor.VisitAlternativePattern(val)
+	case ListPattern:
+		return visitor.VisitListPattern(val)
+	case StructPattern:
+		return visitor.VisitStructPattern(val)
+	case EnumPattern:
+		return visitor.VisitEnumPattern(val)
+	}
+	return *new(E)
+}
@@ -17,17 +17,74 @@ type BlockStmt struct {
 type IfStmt struct {
 	Condition Expr
 	Block     Stmt
+	Else      Stmt
 }
 type VarDeclStmt struct {
+	Keyword    tokenizer.Token
 	Name       tokenizer.Token
+	Type       TypeExpr
 	Expression Expr
 }
 type ForStmt struct {
+	Label         tokenizer.Token
 	PreStatement  Stmt
 	Condition     Expr
 	PostStatement Expr
 	Block         Stmt
 }
+type FuncDeclStmt struct {
+	Name       tokenizer.Token
+	TypeParams []tokenizer.Token
+	Bounds     [][]tokenizer.Token
+	Params     []tokenizer.Token
+	ParamTypes []TypeExpr
+	Return     TypeExpr
+	Body       []Stmt
+}
+type StructDeclStmt struct {
+	Name       tokenizer.Token
+	TypeParams []tokenizer.Token
+	Bounds     [][]tokenizer.Token
+	Fields     []tokenizer.Token
+	FieldTypes []TypeExpr
+	Traits     []tokenizer.Token
+	Methods    []FuncDeclStmt
+}
+type TraitDeclStmt struct {
+	Name     tokenizer.Token
+	Methods  []FuncDeclStmt
+	Defaults []FuncDeclStmt
+}
+type EnumDeclStmt struct {
+	Name       tokenizer.Token
+	Variants   []tokenizer.Token
+	Fields     [][]tokenizer.Token
+	FieldTypes [][]TypeExpr
+}
+type ReturnStmt struct {
+	Keyword tokenizer.Token
+	Value   Expr
+}
+type WhileStmt struct {
+	Label     tokenizer.Token
+	Condition Expr
+	Block     Stmt
+}
+type BreakStmt struct {
+	Keyword tokenizer.Token
+	Label   tokenizer.Token
+}
+type ContinueStmt struct {
+	Keyword tokenizer.Token
+	Label   tokenizer.Token
+}
+type MatchStmt struct {
+	Keyword  tokenizer.Token
+	Value    Expr
+	Patterns []Pattern
+	Guards   []Expr
+	Bodies   []Stmt
+}
 type StmtVisitor[E any] interface {
 	VisitExpressionStmt(stmt ExpressionStmt) E
 	VisitPrintStmt(stmt PrintStmt) E
@@ -35,6 +92,15 @@ type StmtVisitor[E any] interface {
 	VisitIfStmt(stmt IfStmt) E
 	VisitVarDeclStmt(stmt VarDeclStmt) E
 	VisitForStmt(stmt ForStmt) E
+	VisitFuncDeclStmt(stmt FuncDeclStmt) E
+	VisitStructDeclStmt(stmt StructDeclStmt) E
+	VisitTraitDeclStmt(stmt TraitDeclStmt) E
+	VisitEnumDeclStmt(stmt EnumDeclStmt) E
+	VisitReturnStmt(stmt ReturnStmt) E
+	VisitWhileStmt(stmt WhileStmt) E
+	VisitBreakStmt(stmt BreakStmt) E
+	VisitContinueStmt(stmt ContinueStmt) E
+	VisitMatchStmt(stmt MatchStmt) E
 }
 
 func AcceptStmt[E any](visitor StmtVisitor[E], stmt Stmt) E {
@@ -51,6 +117,24 @@ func AcceptStmt[E any](visitor StmtVisitor[E], stmt Stmt) E {
 		return visitor.VisitVarDeclStmt(val)
 	case ForStmt:
 		return visitor.VisitForStmt(val)
+	case FuncDeclStmt:
+		return visitor.VisitFuncDeclStmt(val)
+	case StructDeclStmt:
+		return visitor.VisitStructDeclStmt(val)
+	case TraitDeclStmt:
+		return visitor.VisitTraitDeclStmt(val)
+	case EnumDeclStmt:
+		return visitor.VisitEnumDeclStmt(val)
+	case ReturnStmt:
+		return visitor.VisitReturnStmt(val)
+	case WhileStmt:
+		return visitor.VisitWhileStmt(val)
+	case BreakStmt:
+		return visitor.VisitBreakStmt(val)
+	case ContinueStmt:
+		return visitor.VisitContinueStmt(val)
+	case MatchStmt:
+		return visitor.VisitMatchStmt(val)
 	}
 	return *new(E)
 }
@@ -0,0 +1,30 @@
+package syntaxtree
+
+import (
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+type TypeExpr interface{}
+type NamedType struct {
+	Name      tokenizer.Token
+	Arguments []TypeExpr
+}
+type FunctionType struct {
+	Keyword tokenizer.Token
+	Params  []TypeExpr
+	Return  TypeExpr
+}
+type TypeExprVisitor[E any] interface {
+	VisitNamedType(typeexpr NamedType) E
+	VisitFunctionType(typeexpr FunctionType) E
+}
+
+func AcceptTypeExpr[E any](visitor TypeExprVisitor[E], typeexpr TypeExpr) E {
+	switch val := typeexpr.(type) {
+	case NamedType:
+		return visitor.VisitNamedType(val)
+	case FunctionType:
+		return visitor.VisitFunctionType(val)
+	}
+	return *new(E)
+}
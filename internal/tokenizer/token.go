@@ -2,6 +2,9 @@ package tokenizer
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 )
 
 type TokenType int
@@ -14,6 +17,8 @@ const (
 	RIGHT_BRACE
 	LEFT_PAREN
 	RIGHT_PAREN
+	LEFT_BRACKET
+	RIGHT_BRACKET
 	PIPE
 	AMPERSAND
 	PLUS
@@ -23,20 +28,40 @@ const (
 	SEMICOLON
 	COMMA
 	DOT
+	COLON
 	EXCLAMATION
 	EQUAL
 	LESS
 	GREATER
+	PERCENT
+	CARET
+	TILDE
+	QUESTION
 
 	// 2 CHARACTERS
 	EXCLAMATION_EQUAL
 	EQUAL_EQUAL
 	LESS_EQUAL
 	GREATER_EQUAL
+	STAR_STAR
+	LESS_LESS
+	GREATER_GREATER
+	PLUS_EQUAL
+	MINUS_EQUAL
+	STAR_EQUAL
+	SLASH_EQUAL
+	PERCENT_EQUAL
+	PLUS_PLUS
+	MINUS_MINUS
+	ARROW
+	MINUS_GREATER
 
 	// MULTIPLE CHARACTERS
 	IDENTIFIER
 	STRING
+	// literal text of an interpolated string up to a ${, followed by the tokens of the embedded
+	// expression; the text after the last embedded expression is a STRING token
+	INTERPOLATION
 	NUMBER
 
 	// KEYWORDS
@@ -53,6 +78,15 @@ const (
 	PRINT
 	TRUE
 	FALSE
+	NIL
+	IN
+	BREAK
+	CONTINUE
+	MATCH
+	ENUM
+	TRAIT
+	LET
+	CONST
 
 	EOF
 )
@@ -69,9 +103,16 @@ type tokenizer struct {
 	start   int
 	current int
 	line    int
+	// tokens already scanned by an interpolated string, handed out before scanning further
+	pending []Token
 }
 
 func (t *tokenizer) TakeToken() (Token, error) {
+	if len(t.pending) > 0 {
+		token := t.pending[0]
+		t.pending = t.pending[1:]
+		return token, nil
+	}
 	if t.IsAtEnd() {
 		return Token{Type: EOF}, nil
 	}
@@ -86,24 +127,86 @@ func (t *tokenizer) TakeToken() (Token, error) {
 		return Token{LEFT_PAREN, "(", 1, t.line}, nil
 	case ')':
 		return Token{RIGHT_PAREN, ")", 1, t.line}, nil
+	case '[':
+		return Token{LEFT_BRACKET, "[", 1, t.line}, nil
+	case ']':
+		return Token{RIGHT_BRACKET, "]", 1, t.line}, nil
 	case '+':
-		return Token{PLUS, "+", 1, t.line}, nil
+		if t.Match('=') {
+			t.Advance()
+			return Token{PLUS_EQUAL, "+=", 2, t.line}, nil
+		} else if t.Match('+') {
+			t.Advance()
+			return Token{PLUS_PLUS, "++", 2, t.line}, nil
+		} else {
+			return Token{PLUS, "+", 1, t.line}, nil
+		}
 	case '-':
-		return Token{MINUS, "-", 1, t.line}, nil
+		if t.Match('=') {
+			t.Advance()
+			return Token{MINUS_EQUAL, "-=", 2, t.line}, nil
+		} else if t.Match('-') {
+			t.Advance()
+			return Token{MINUS_MINUS, "--", 2, t.line}, nil
+		} else if t.Match('>') {
+			t.Advance()
+			return Token{MINUS_GREATER, "->", 2, t.line}, nil
+		} else {
+			return Token{MINUS, "-", 1, t.line}, nil
+		}
+	case '%':
+		if t.Match('=') {
+			t.Advance()
+			return Token{PERCENT_EQUAL, "%=", 2, t.line}, nil
+		} else {
+			return Token{PERCENT, "%", 1, t.line}, nil
+		}
+	case '^':
+		return Token{CARET, "^", 1, t.line}, nil
+	case '~':
+		return Token{TILDE, "~", 1, t.line}, nil
+	case '?':
+		return Token{QUESTION, "?", 1, t.line}, nil
 	case '|':
 		return Token{PIPE, "|", 1, t.line}, nil
 	case '&':
 		return Token{AMPERSAND, "&", 1, t.line}, nil
 	case '/':
-		return Token{SLASH, "/", 1, t.line}, nil
+		if t.Match('/') {
+			for t.Peak() != '\n' && !t.IsAtEnd() {
+				t.Advance()
+			}
+			return t.TakeToken()
+		} else if t.Match('*') {
+			t.Advance()
+			if err := t.SkipBlockComment(); err != nil {
+				return Token{Type: UNIDENTIFIED}, err
+			}
+			return t.TakeToken()
+		} else if t.Match('=') {
+			t.Advance()
+			return Token{SLASH_EQUAL, "/=", 2, t.line}, nil
+		} else {
+			return Token{SLASH, "/", 1, t.line}, nil
+		}
 	case '*':
-		return Token{STAR, "*", 1, t.line}, nil
+		if t.Match('*') {
+			t.Advance()
+			return Token{STAR_STAR, "**", 2, t.line}, nil
+		} else if t.Match('=') {
+			t.Advance()
+			return Token{STAR_EQUAL, "*=", 2, t.line}, nil
+		} else {
+			return Token{STAR, "*", 1, t.line}, nil
+		}
 	case ';':
 		return Token{SEMICOLON, ";", 1, t.line}, nil
 	case '.':
 		return Token{DOT, ".", 1, t.line}, nil
 	case ',':
 		return Token{COMMA, ",", 1, t.line}, nil
+	case ':':
+		return Token{COLON, ":", 1, t.line}, nil
 	case '!':
 		if t.Match('=') {
 			t.Advance()
@@ -115,6 +218,9 @@ func (t *tokenizer) TakeToken() (Token, error) {
 		if t.Match('=') {
 			t.Advance()
 			return Token{EQUAL_EQUAL, "==", 2, t.line}, nil
+		} else if t.Match('>') {
+			t.Advance()
+			return Token{ARROW, "=>", 2, t.line}, nil
 		} else {
 			return Token{EQUAL, "=", 1, t.line}, nil
 		}
@@ -122,15 +228,21 @@ func (t *tokenizer) TakeToken() (Token, error) {
 		if t.Match('=') {
 			t.Advance()
 			return Token{LESS_EQUAL, "<=", 2, t.line}, nil
+		} else if t.Match('<') {
+			t.Advance()
+			return Token{LESS_LESS, "<<", 2, t.line}, nil
 		} else {
 			return Token{LESS, "<", 1, t.line}, nil
 		}
 	case '>':
 		if t.Match('=') {
 			t.Advance()
-			return Token{GREATER_EQUAL, "!=", 2, t.line}, nil
+			return Token{GREATER_EQUAL, ">=", 2, t.line}, nil
+		} else if t.Match('>') {
+			t.Advance()
+			return Token{GREATER_GREATER, ">>", 2, t.line}, nil
 		} else {
-			return Token{GREATER, "!", 1, t.line}, nil
+			return Token{GREATER, ">", 1, t.line}, nil
 		}
 	case ' ', '\t', '\r':
 		return t.TakeToken()
@@ -138,29 +250,20 @@ func (t *tokenizer) TakeToken() (Token, error) {
 		t.line += 1
 		return t.TakeToken()
 	case '"':
-		for t.Peak() != '"' && t.Peak() != 0 {
+		if t.Match('"') && t.PeakNext(1) == '"' {
 			t.Advance()
+			t.Advance()
+			return t.TripleString()
 		}
-		if t.Peak() == 0 {
-			return Token{Type: UNIDENTIFIED}, errors.New("unclosed string")
-		}
-		t.Advance()
-		return Token{STRING, string(t.data[t.start+1 : t.current-1]), t.current - t.start - 2, t.line}, nil
+		return t.String()
+	case '`':
+		return t.RawString()
 	}
 
 	switch {
 	case t.IsDigit(c):
-		for t.IsDigit(t.Peak()) {
-			t.Advance()
-		}
-		if t.Peak() == '.' {
-			t.Advance()
-			if !t.IsDigit(t.Peak()) {
-				return Token{Type: UNIDENTIFIED}, errors.New("unclosed float number")
-			}
-			for t.IsDigit(t.Peak()) {
-				t.Advance()
-			}
+		if err := t.Number(c); err != nil {
+			return Token{Type: UNIDENTIFIED}, err
 		}
 		return Token{NUMBER, string(t.data[t.start:t.current]), t.current - t.start, t.line}, nil
 
@@ -180,6 +283,15 @@ func (t *tokenizer) TakeToken() (Token, error) {
 	keywords["print"] = PRINT
 	keywords["true"] = TRUE
 	keywords["false"] = FALSE
+	keywords["nil"] = NIL
+	keywords["in"] = IN
+	keywords["break"] = BREAK
+	keywords["continue"] = CONTINUE
+	keywords["match"] = MATCH
+	keywords["enum"] = ENUM
+	keywords["trait"] = TRAIT
+	keywords["let"] = LET
+	keywords["const"] = CONST
 
 	for t.IsDigit(t.Peak()) || t.IsGoodChar(t.Peak()) {
 		t.Advance()
@@ -194,10 +306,322 @@ func (t *tokenizer) TakeToken() (Token, error) {
 	}
 }
 
+// String scans a string literal after its opening quote and decodes escape sequences.
+// Every ${ starts an embedded expression that is tokenized up to its matching }
+func (t *tokenizer) String() (Token, error) {
+	startLine := t.line
+	segmentLine := t.line
+	segment := t.current
+	var tokens []Token
+	for t.Peak() != '"' && !t.IsAtEnd() {
+		if t.Peak() == '$' && t.PeakNext(1) == '{' {
+			value, err := t.Unescape(string(t.data[segment:t.current]), segmentLine)
+			if err != nil {
+				return Token{Type: UNIDENTIFIED}, err
+			}
+			tokens = append(tokens, Token{INTERPOLATION, value, len(value), segmentLine})
+			t.Advance()
+			t.Advance()
+			inner, err := t.Interpolation(startLine)
+			if err != nil {
+				return Token{Type: UNIDENTIFIED}, err
+			}
+			tokens = append(tokens, inner...)
+			segment = t.current
+			segmentLine = t.line
+			continue
+		}
+		c := t.Advance()
+		if c == '\\' && !t.IsAtEnd() {
+			c = t.Advance()
+		}
+		if c == '\n' {
+			t.line += 1
+		}
+	}
+	if t.IsAtEnd() {
+		return Token{Type: UNIDENTIFIED}, errors.New("unclosed string starting on line " + strconv.Itoa(startLine))
+	}
+	t.Advance()
+	value, err := t.Unescape(string(t.data[segment:t.current-1]), segmentLine)
+	if err != nil {
+		return Token{Type: UNIDENTIFIED}, err
+	}
+	tokens = append(tokens, Token{STRING, value, len(value), segmentLine})
+	t.pending = append(t.pending, tokens[1:]...)
+	return tokens[0], nil
+}
+
+// Interpolation takes the tokens of an embedded expression after its ${, the closing } is consumed but not returned
+func (t *tokenizer) Interpolation(startLine int) ([]Token, error) {
+	var tokens []Token
+	depth := 0
+	for {
+		token, err := t.TakeToken()
+		if err != nil {
+			return nil, err
+		}
+		switch token.Type {
+		case EOF:
+			return nil, errors.New("unclosed ${ in string starting on line " + strconv.Itoa(startLine))
+		case LEFT_BRACE:
+			depth += 1
+		case RIGHT_BRACE:
+			if depth == 0 && len(tokens) == 0 {
+				return nil, errors.New("empty ${} in string on line " + strconv.Itoa(token.Line))
+			}
+			if depth == 0 {
+				return tokens, nil
+			}
+			depth -= 1
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+// RawString scans a backtick string, its content is taken verbatim
+func (t *tokenizer) RawString() (Token, error) {
+	startLine := t.line
+	for t.Peak() != '`' && !t.IsAtEnd() {
+		if t.Advance() == '\n' {
+			t.line += 1
+		}
+	}
+	if t.IsAtEnd() {
+		return Token{Type: UNIDENTIFIED}, errors.New("unclosed raw string starting on line " + strconv.Itoa(startLine))
+	}
+	t.Advance()
+	value := string(t.data[t.start+1 : t.current-1])
+	return Token{STRING, value, len(value), startLine}, nil
+}
+
+// TripleString scans a multi-line string after its opening """.
+// A line break right after the opening quotes is dropped, and so is the last line
+// when it holds nothing but the indentation of the closing quotes.
+// The indentation common to all non-blank lines is stripped before escapes are decoded.
+func (t *tokenizer) TripleString() (Token, error) {
+	startLine := t.line
+	begin := t.current
+	for !(t.Peak() == '"' && t.PeakNext(1) == '"' && t.PeakNext(2) == '"') && !t.IsAtEnd() {
+		c := t.Advance()
+		if c == '\\' && !t.IsAtEnd() {
+			c = t.Advance()
+		}
+		if c == '\n' {
+			t.line += 1
+		}
+	}
+	if t.IsAtEnd() {
+		return Token{Type: UNIDENTIFIED}, errors.New("unclosed multi-line string starting on line " + strconv.Itoa(startLine))
+	}
+	raw := string(t.data[begin:t.current])
+	t.Advance()
+	t.Advance()
+	t.Advance()
+
+	lines := strings.Split(raw, "\n")
+	firstLine := startLine
+	if len(lines) > 1 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+		firstLine += 1
+	}
+	if len(lines) > 1 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	indent := ""
+	found := false
+	for _, v := range lines {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		current := v[:len(v)-len(strings.TrimLeft(v, " \t"))]
+		if !found {
+			indent = current
+			found = true
+		}
+		for !strings.HasPrefix(current, indent) {
+			indent = indent[:len(indent)-1]
+		}
+	}
+	var value strings.Builder
+	for i, v := range lines {
+		if i > 0 {
+			value.WriteByte('\n')
+		}
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		decoded, err := t.Unescape(strings.TrimPrefix(v, indent), firstLine+i)
+		if err != nil {
+			return Token{Type: UNIDENTIFIED}, err
+		}
+		value.WriteString(decoded)
+	}
+	return Token{STRING, value.String(), value.Len(), startLine}, nil
+}
+
+// Unescape decodes the escape sequences of a string literal that starts on line
+func (t *tokenizer) Unescape(raw string, line int) (string, error) {
+	var result strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\n' {
+			line += 1
+		}
+		if raw[i] != '\\' {
+			result.WriteByte(raw[i])
+			continue
+		}
+		i++
+		if i == len(raw) {
+			return "", errors.New("unfinished escape sequence on line " + strconv.Itoa(line))
+		}
+		switch raw[i] {
+		case 'n':
+			result.WriteByte('\n')
+		case 't':
+			result.WriteByte('\t')
+		case 'r':
+			result.WriteByte('\r')
+		case '0':
+			result.WriteByte(0)
+		case '\\', '"', '\'', '$':
+			result.WriteByte(raw[i])
+		case 'u':
+			end := strings.IndexByte(raw[i:], '}')
+			if i+1 >= len(raw) || raw[i+1] != '{' || end == -1 {
+				return "", errors.New("expected \\u{...} escape sequence on line " + strconv.Itoa(line))
+			}
+			code, err := strconv.ParseUint(raw[i+2:i+end], 16, 32)
+			if err != nil || end-2 > 6 || !utf8.ValidRune(rune(code)) {
+				return "", errors.New("invalid unicode escape \\" + raw[i:i+end+1] + " on line " + strconv.Itoa(line))
+			}
+			result.WriteRune(rune(code))
+			i += end
+		default:
+			return "", errors.New("invalid escape sequence \\" + string(raw[i]) + " on line " + strconv.Itoa(line))
+		}
+	}
+	return result.String(), nil
+}
+
+// Number scans the rest of a number literal starting with digit first:
+// 0x, 0b and 0o prefixed integers, decimals with an optional fraction and exponent,
+// and _ separators between digits
+func (t *tokenizer) Number(first byte) error {
+	isDigit := t.IsDigit
+	kind := "number"
+	if first == '0' {
+		switch t.Peak() {
+		case 'x', 'X':
+			isDigit, kind = t.IsHexDigit, "hexadecimal"
+		case 'b', 'B':
+			isDigit, kind = func(c byte) bool { return c == '0' || c == '1' }, "binary"
+		case 'o', 'O':
+			isDigit, kind = func(c byte) bool { return c >= '0' && c <= '7' }, "octal"
+		}
+	}
+	if kind != "number" {
+		t.Advance()
+		if t.Peak() == '_' {
+			t.Advance()
+		}
+		if !isDigit(t.Peak()) {
+			return t.NumberError("expected "+kind+" digit", t.current)
+		}
+	}
+	if err := t.Digits(isDigit, kind); err != nil {
+		return err
+	}
+	if kind == "number" && t.Peak() == '.' {
+		t.Advance()
+		if !t.IsDigit(t.Peak()) {
+			return t.NumberError("expected digit after .", t.current)
+		}
+		if err := t.Digits(t.IsDigit, kind); err != nil {
+			return err
+		}
+	}
+	if kind == "number" && (t.Peak() == 'e' || t.Peak() == 'E') {
+		t.Advance()
+		if t.Peak() == '+' || t.Peak() == '-' {
+			t.Advance()
+		}
+		if !t.IsDigit(t.Peak()) {
+			return t.NumberError("expected digit in exponent", t.current)
+		}
+		if err := t.Digits(t.IsDigit, kind); err != nil {
+			return err
+		}
+	}
+	if t.IsDigit(t.Peak()) || t.IsGoodChar(t.Peak()) {
+		return t.NumberError("invalid character '"+string(t.Peak())+"' in "+kind+" literal", t.current)
+	}
+	text := string(t.data[t.start:t.current])
+	if IsFloat(text) {
+		if _, err := ParseFloat(text); err != nil {
+			return t.NumberError("float literal "+text+" out of range", t.start)
+		}
+	} else if _, err := ParseInt(text); err != nil {
+		return t.NumberError("integer literal "+text+" out of range", t.start)
+	}
+	return nil
+}
+
+// Digits consumes digits, a _ is only accepted between two digits
+func (t *tokenizer) Digits(isDigit func(byte) bool, kind string) error {
+	for isDigit(t.Peak()) || t.Peak() == '_' {
+		if t.Advance() == '_' && !isDigit(t.Peak()) {
+			return t.NumberError("_ must separate digits in "+kind+" literal", t.current)
+		}
+	}
+	return nil
+}
+
+// NumberError reports a malformed number literal at the byte pos
+func (t *tokenizer) NumberError(str string, pos int) error {
+	return errors.New("[" + str + "] on line " + strconv.Itoa(t.line) + ", column " + strconv.Itoa(t.Column(pos)))
+}
+
+// Column is the 1-based column of the byte at pos
+func (t *tokenizer) Column(pos int) int {
+	column := 1
+	for i := pos - 1; i >= 0 && t.data[i] != '\n'; i-- {
+		column += 1
+	}
+	return column
+}
+
+// SkipBlockComment skips past the */ closing an already opened comment, comments nest
+func (t *tokenizer) SkipBlockComment() error {
+	startLine := t.line
+	depth := 1
+	for depth > 0 {
+		if t.IsAtEnd() {
+			return errors.New("unclosed block comment starting on line " + strconv.Itoa(startLine))
+		}
+		c := t.Advance()
+		switch {
+		case c == '\n':
+			t.line += 1
+		case c == '/' && t.Match('*'):
+			t.Advance()
+			depth += 1
+		case c == '*' && t.Match('/'):
+			t.Advance()
+			depth -= 1
+		}
+	}
+	return nil
+}
+
 func (t *tokenizer) IsDigit(char byte) bool {
 	return char >= '0' && char <= '9'
 }
 
+func (t *tokenizer) IsHexDigit(char byte) bool {
+	return t.IsDigit(char) || char >= 'a' && char <= 'f' || char >= 'A' && char <= 'F'
+}
+
 func (t *tokenizer) IsGoodChar(char byte) bool {
 	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char == '_'
 }
@@ -241,7 +665,7 @@ func (t *tokenizer) PeakNext(off int) byte {
 func Tokenize(data []byte) ([]Token, error) {
 	var tokens []Token
 	var tk = tokenizer{data: data, line: 1}
-	for !tk.IsAtEnd() {
+	for !tk.IsAtEnd() || len(tk.pending) > 0 {
 		token, err := tk.TakeToken()
 		if err != nil {
 			return nil, err
@@ -253,3 +677,37 @@ func Tokenize(data []byte) ([]Token, error) {
 	}
 	return tokens, nil
 }
+
+// IsFloat reports whether a NUMBER token is a float literal
+func IsFloat(content string) bool {
+	if len(content) > 1 && content[0] == '0' && strings.ContainsRune("xXbBoO", rune(content[1])) {
+		return false
+	}
+	return strings.ContainsAny(content, ".eE")
+}
+
+// ParseInt decodes an integer NUMBER token in any of its bases
+func ParseInt(content string) (int, error) {
+	content = strings.ReplaceAll(content, "_", "")
+	base := 10
+	if len(content) > 1 && content[0] == '0' {
+		switch content[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		case 'o', 'O':
+			base = 8
+		}
+	}
+	if base != 10 {
+		content = content[2:]
+	}
+	res, err := strconv.ParseInt(content, base, strconv.IntSize)
+	return int(res), err
+}
+
+// ParseFloat decodes a float NUMBER token
+func ParseFloat(content string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(content, "_", ""), 64)
+}
@@ -0,0 +1,116 @@
+package tokenizer
+
+import (
+	"strings"
+	"testing"
+)
+
+// contents joins the contents of all tokens but the trailing EOF with spaces
+func contents(t *testing.T, source string) string {
+	tokens, err := Tokenize([]byte(source))
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	var parts []string
+	for _, v := range tokens {
+		if v.Type != EOF {
+			parts = append(parts, v.Content)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestComments(t *testing.T) {
+	tests := []struct {
+		source string
+		tokens string
+	}{
+		{"1 // 2\n3", "1 3"},
+		{"1 /* 2 */ 3", "1 3"},
+		{"1 /* a /* nested */ still comment */ 2", "1 2"},
+		{"1 /* a\nb */ / 2", "1 / 2"},
+		{"// only", ""},
+	}
+	for _, test := range tests {
+		if got := contents(t, test.source); got != test.tokens {
+			t.Errorf("%q: got %q, want %q", test.source, got, test.tokens)
+		}
+	}
+	if _, err := Tokenize([]byte("1 /* open /* */")); err == nil {
+		t.Error("unterminated comment was accepted")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	tests := []struct {
+		source  string
+		content string
+	}{
+		{`"plain"`, "plain"},
+		{`"a\nb\tc"`, "a\nb\tc"},
+		{`"quote \" and \\"`, `quote " and \`},
+		{`"\u{48}\u{49}"`, "HI"},
+		{`"\$"`, "$"},
+		{"`raw \\n ${x}`", `raw \n ${x}`},
+		{"\"\"\"\n    one\n      two\n    \"\"\"", "one\n  two"},
+	}
+	for _, test := range tests {
+		tokens, err := Tokenize([]byte(test.source))
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		if tokens[0].Type != STRING || tokens[0].Content != test.content {
+			t.Errorf("%s: got %q, want %q", test.source, tokens[0].Content, test.content)
+		}
+	}
+	for _, source := range []string{`"open`, `"\q"`, `"\u{110000}"`, `"${1"`} {
+		if _, err := Tokenize([]byte(source)); err == nil {
+			t.Errorf("%s: expected an error", source)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	tests := []struct {
+		source  string
+		isFloat bool
+		integer int
+		float   float64
+	}{
+		{"42", false, 42, 0},
+		{"1_000_000", false, 1000000, 0},
+		{"0xFF", false, 255, 0},
+		{"0b1010", false, 10, 0},
+		{"0o755", false, 493, 0},
+		{"0", false, 0, 0},
+		{"3.14", true, 0, 3.14},
+		{"1.5e-3", true, 0, 0.0015},
+		{"2E3", true, 0, 2000},
+		{"1_000.5", true, 0, 1000.5},
+	}
+	for _, test := range tests {
+		tokens, err := Tokenize([]byte(test.source))
+		if err != nil {
+			t.Errorf("%s: %v", test.source, err)
+			continue
+		}
+		token := tokens[0]
+		if token.Type != NUMBER || token.Content != test.source || IsFloat(token.Content) != test.isFloat {
+			t.Errorf("%s: got token %v", test.source, token)
+			continue
+		}
+		if test.isFloat {
+			if val, err := ParseFloat(token.Content); err != nil || val != test.float {
+				t.Errorf("%s: ParseFloat gave %v, %v", test.source, val, err)
+			}
+		} else if val, err := ParseInt(token.Content); err != nil || val != test.integer {
+			t.Errorf("%s: ParseInt gave %v, %v", test.source, val, err)
+		}
+	}
+	for _, source := range []string{"1__0", "1_", "0x", "0b12", "1.5e"} {
+		if _, err := Tokenize([]byte(source)); err == nil {
+			t.Errorf("%s: expected an error", source)
+		}
+	}
+}
@@ -0,0 +1,1185 @@
+package types
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/WhoDoIt/GoCompiler/internal/syntaxtree"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+type env struct {
+	vars  map[string]Type
+	types map[string]Type
+	// the let or const keyword of the vars declared with one
+	immutable map[string]tokenizer.Token
+	// the index in the declarations of the vars declared without an annotation
+	inferred map[string]int
+	parent   *env
+}
+
+func newEnv(parent *env) *env {
+	return &env{vars: make(map[string]Type), types: make(map[string]Type), immutable: make(map[string]tokenizer.Token), inferred: make(map[string]int), parent: parent}
+}
+
+// lookup returns the type of a variable, variables the checker does not know about are Any
+func (e *env) lookup(name string) Type {
+	for ; e != nil; e = e.parent {
+		if val, ok := e.vars[name]; ok {
+			return val
+		}
+	}
+	return Any
+}
+
+// lookupImmutable returns the keyword name was declared with when it is a let or const
+func (e *env) lookupImmutable(name string) (tokenizer.Token, bool) {
+	for ; e != nil; e = e.parent {
+		if _, ok := e.vars[name]; ok {
+			keyword, ok := e.immutable[name]
+			return keyword, ok
+		}
+	}
+	return tokenizer.Token{}, false
+}
+
+// lookupInferred returns the env of name when it is a var declared without an annotation
+func (e *env) lookupInferred(name string) (*env, bool) {
+	for ; e != nil; e = e.parent {
+		if _, ok := e.vars[name]; ok {
+			_, ok := e.inferred[name]
+			return e, ok
+		}
+	}
+	return nil, false
+}
+
+func (e *env) lookupType(name string) (Type, bool) {
+	for ; e != nil; e = e.parent {
+		if val, ok := e.types[name]; ok {
+			return val, true
+		}
+	}
+	return nil, false
+}
+
+var compoundOperators = map[tokenizer.TokenType]tokenizer.TokenType{
+	tokenizer.PLUS_EQUAL:    tokenizer.PLUS,
+	tokenizer.MINUS_EQUAL:   tokenizer.MINUS,
+	tokenizer.STAR_EQUAL:    tokenizer.STAR,
+	tokenizer.SLASH_EQUAL:   tokenizer.SLASH,
+	tokenizer.PERCENT_EQUAL: tokenizer.PERCENT,
+	tokenizer.PLUS_PLUS:     tokenizer.PLUS,
+	tokenizer.MINUS_MINUS:   tokenizer.MINUS,
+}
+
+// Declaration is the type the checker settled on for a declared name
+type Declaration struct {
+	Name string
+	Line int
+	Type Type
+}
+
+func (d Declaration) String() string {
+	return d.Name + ": " + d.Type.String() + " on line " + strconv.Itoa(d.Line)
+}
+
+type checker struct {
+	env          *env
+	errors       []error
+	declarations []Declaration
+	// declared return type of the function being checked, nil outside of functions and while it is inferred
+	returns Type
+	// types of the values returned so far by the function being inferred
+	returned []Type
+	// bound checks of the annotations met while a block is declared, non-nil only then,
+	// they wait until the structs of the block have their methods
+	pending []func()
+}
+
+// Check walks the program before it runs, it returns the types of all declarations and every type error it finds
+func Check(stmt []syntaxtree.Stmt) ([]Declaration, []error) {
+	c := checker{env: newEnv(nil)}
+	for name, typ := range builtins() {
+		c.env.vars[name] = typ
+	}
+	for _, v := range builtinTraits() {
+		c.env.types[v.Name] = v
+	}
+	c.block(stmt)
+	return c.declarations, c.errors
+}
+
+func builtins() map[string]Type {
+	t, k, v := &TypeParam{Name: "T"}, &TypeParam{Name: "K"}, &TypeParam{Name: "V"}
+	return map[string]Type{
+		"int":    &Func{Params: []Type{Any}, Return: Int},
+		"float":  &Func{Params: []Type{Any}, Return: Float},
+		"str":    &Func{Params: []Type{Any}, Return: String},
+		"len":    &Func{Params: []Type{Any}, Return: Int},
+		"push":   &Func{TypeParams: []*TypeParam{t}, Params: []Type{List{Elem: t}, t}, Return: Nil},
+		"pop":    &Func{TypeParams: []*TypeParam{t}, Params: []Type{List{Elem: t}}, Return: t},
+		"delete": &Func{TypeParams: []*TypeParam{k, v}, Params: []Type{Map{Key: k, Value: v}, k}, Return: Any},
+		"keys":   &Func{TypeParams: []*TypeParam{k, v}, Params: []Type{Map{Key: k, Value: v}}, Return: List{Elem: k}},
+		"values": &Func{TypeParams: []*TypeParam{k, v}, Params: []Type{Map{Key: k, Value: v}}, Return: List{Elem: v}},
+	}
+}
+
+// builtinTraits are the traits the interpreter dispatches through, print calls show, == calls eq and < calls less
+func builtinTraits() []*Trait {
+	return []*Trait{
+		{Name: "Display", Methods: []string{"show"}, Types: map[string]*Func{"show": {Return: String}}},
+		{Name: "Eq", Methods: []string{"eq"}, Types: map[string]*Func{"eq": {Params: []Type{Any}, Return: Bool}}},
+		{Name: "Ord", Methods: []string{"less"}, Types: map[string]*Func{"less": {Params: []Type{Any}, Return: Bool}}},
+	}
+}
+
+func (c *checker) error(token tokenizer.Token, str string) {
+	c.errors = append(c.errors, errors.New("["+str+"] on line "+strconv.Itoa(token.Line)))
+}
+
+func (c *checker) expr(expr syntaxtree.Expr) Type {
+	return syntaxtree.AcceptExpr(c, expr)
+}
+
+// exprExpecting checks expr where a value of type expected is needed, so that the parameters of a lambda
+// without annotations take their types from it
+func (c *checker) exprExpecting(expr syntaxtree.Expr, expected Type) Type {
+	if lambda, ok := expr.(syntaxtree.FunctionExpr); ok {
+		return c.lambda(lambda, expected)
+	}
+	return c.expr(expr)
+}
+
+func (c *checker) declared(name tokenizer.Token, typ Type) {
+	c.declarations = append(c.declarations, Declaration{Name: name.Content, Line: name.Line, Type: typ})
+}
+
+func (c *checker) stmt(stmt syntaxtree.Stmt) {
+	syntaxtree.AcceptStmt(c, stmt)
+}
+
+// block checks statements sharing the current env, declarations are visible to the whole block
+func (c *checker) block(stmt []syntaxtree.Stmt) {
+	redeclared := c.declare(stmt)
+	for i, v := range stmt {
+		if !redeclared[i] {
+			c.stmt(v)
+		}
+	}
+}
+
+// declaredName is the name a trait, struct, enum or fn declaration binds for the whole of its block
+func declaredName(stmt syntaxtree.Stmt) (tokenizer.Token, bool) {
+	switch stmt := stmt.(type) {
+	case syntaxtree.TraitDeclStmt:
+		return stmt.Name, true
+	case syntaxtree.StructDeclStmt:
+		return stmt.Name, true
+	case syntaxtree.EnumDeclStmt:
+		return stmt.Name, true
+	case syntaxtree.FuncDeclStmt:
+		return stmt.Name, true
+	}
+	return tokenizer.Token{}, false
+}
+
+// declare introduces the traits, structs, enums and fns of a block before any of its statements are checked,
+// names first so that declarations can refer to each other in any order and traits before the structs declaring them,
+// it returns the indices of the declarations reusing a name of the block, they are reported and left out
+func (c *checker) declare(stmt []syntaxtree.Stmt) map[int]bool {
+	c.pending = []func(){}
+	redeclared := make(map[int]bool)
+	names := make(map[string]bool)
+	for i, v := range stmt {
+		if name, ok := declaredName(v); ok {
+			if names[name.Content] {
+				c.error(name, name.Content+" already declared in this scope")
+				redeclared[i] = true
+			}
+			names[name.Content] = true
+		}
+	}
+	for i, v := range stmt {
+		if redeclared[i] {
+			continue
+		}
+		switch v := v.(type) {
+		case syntaxtree.StructDeclStmt:
+			c.env.types[v.Name.Content] = &Struct{Name: v.Name.Content, FieldTypes: make(map[string]Type), Methods: make(map[string]*Func)}
+		case syntaxtree.EnumDeclStmt:
+			c.env.types[v.Name.Content] = &Enum{Name: v.Name.Content, Fields: make(map[string][]string), Types: make(map[string][]Type)}
+		case syntaxtree.TraitDeclStmt:
+			c.env.types[v.Name.Content] = &Trait{Name: v.Name.Content, Types: make(map[string]*Func)}
+		}
+	}
+	// bounds name traits, so the type parameters of structs are made once every trait of the block is known
+	for i, v := range stmt {
+		if v, ok := v.(syntaxtree.StructDeclStmt); ok && !redeclared[i] {
+			if structType, ok := c.env.types[v.Name.Content].(*Struct); ok {
+				structType.TypeParams = c.typeParams(v.TypeParams, v.Bounds)
+			}
+		}
+	}
+	for i, v := range stmt {
+		if v, ok := v.(syntaxtree.TraitDeclStmt); ok && !redeclared[i] {
+			trait, ok := c.env.types[v.Name.Content].(*Trait)
+			if !ok {
+				continue
+			}
+			for _, method := range v.Methods {
+				trait.Methods = append(trait.Methods, method.Name.Content)
+				trait.Types[method.Name.Content] = c.signature(method.TypeParams, method.Bounds, method.ParamTypes, method.Return)
+			}
+			for _, method := range v.Defaults {
+				trait.Methods = append(trait.Methods, method.Name.Content)
+				trait.Defaults = append(trait.Defaults, method.Name.Content)
+				trait.Types[method.Name.Content] = c.signature(method.TypeParams, method.Bounds, method.ParamTypes, method.Return)
+			}
+		}
+	}
+	for i, v := range stmt {
+		if redeclared[i] {
+			continue
+		}
+		switch v := v.(type) {
+		case syntaxtree.StructDeclStmt:
+			structType, ok := c.env.types[v.Name.Content].(*Struct)
+			if !ok {
+				continue
+			}
+			prev := c.env
+			c.env = typeParamsEnv(prev, structType.TypeParams)
+			var fields []Type
+			for i, field := range v.Fields {
+				structType.Fields = append(structType.Fields, field.Content)
+				structType.FieldTypes[field.Content] = c.resolve(v.FieldTypes[i])
+				fields = append(fields, structType.FieldTypes[field.Content])
+			}
+			for _, method := range v.Methods {
+				structType.Methods[method.Name.Content] = c.signature(method.TypeParams, method.Bounds, method.ParamTypes, method.Return)
+			}
+			c.env = prev
+			for _, name := range v.Traits {
+				val, _ := c.env.lookupType(name.Content)
+				if trait, ok := val.(*Trait); ok {
+					structType.Traits = append(structType.Traits, trait)
+					for _, method := range trait.Defaults {
+						if _, ok := structType.Methods[method]; !ok {
+							structType.Methods[method] = trait.Types[method]
+						}
+					}
+				}
+			}
+			c.env.vars[v.Name.Content] = &Func{TypeParams: structType.TypeParams, Params: fields, Return: selfType(structType)}
+		case syntaxtree.EnumDeclStmt:
+			enum, ok := c.env.types[v.Name.Content].(*Enum)
+			if !ok {
+				continue
+			}
+			for i, variant := range v.Variants {
+				enum.Variants = append(enum.Variants, variant.Content)
+				for j, field := range v.Fields[i] {
+					enum.Fields[variant.Content] = append(enum.Fields[variant.Content], field.Content)
+					enum.Types[variant.Content] = append(enum.Types[variant.Content], c.resolve(v.FieldTypes[i][j]))
+				}
+			}
+			c.env.vars[v.Name.Content] = EnumObject{Enum: enum}
+		case syntaxtree.FuncDeclStmt:
+			c.env.vars[v.Name.Content] = c.signature(v.TypeParams, v.Bounds, v.ParamTypes, v.Return)
+		}
+	}
+	pending := c.pending
+	c.pending = nil
+	for _, check := range pending {
+		check()
+	}
+	return redeclared
+}
+
+// typeParamsEnv is an env in which the type parameters can be named
+func typeParamsEnv(parent *env, params []*TypeParam) *env {
+	if params == nil {
+		return parent
+	}
+	e := newEnv(parent)
+	for _, v := range params {
+		e.types[v.Name] = v
+	}
+	return e
+}
+
+// selfType is the type of instances of a struct inside of its methods
+func selfType(structType *Struct) Type {
+	if structType.TypeParams == nil {
+		return structType
+	}
+	applied := Applied{Struct: structType}
+	for _, v := range structType.TypeParams {
+		applied.Args = append(applied.Args, v)
+	}
+	return applied
+}
+
+// typeParams makes the type parameters of a generic fn or struct, each bound by the traits named for it
+func (c *checker) typeParams(names []tokenizer.Token, bounds [][]tokenizer.Token) []*TypeParam {
+	var params []*TypeParam
+	for i, v := range names {
+		param := &TypeParam{Name: v.Content}
+		for _, name := range bounds[i] {
+			val, ok := c.env.lookupType(name.Content)
+			trait, isTrait := val.(*Trait)
+			if !ok {
+				c.error(name, "unknown trait "+name.Content)
+			} else if !isTrait {
+				c.error(name, name.Content+" is not a trait")
+			} else {
+				param.Bounds = append(param.Bounds, trait)
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// bounded reports the type parameters whose type in subst does not implement the traits they are bound by
+func (c *checker) bounded(token tokenizer.Token, params []*TypeParam, subst map[*TypeParam]Type) {
+	for _, v := range params {
+		typ, ok := subst[v]
+		if !ok || typ == Any {
+			continue
+		}
+		for _, trait := range v.Bounds {
+			if reason := conforms(typ, trait); reason != "" {
+				c.error(token, typ.String()+" does not implement "+trait.Name+", "+reason)
+			}
+		}
+	}
+}
+
+// signature is the type of a fn from its annotations, anything not annotated is Any
+func (c *checker) signature(typeParams []tokenizer.Token, bounds [][]tokenizer.Token, params []syntaxtree.TypeExpr, ret syntaxtree.TypeExpr) *Func {
+	fn := &Func{TypeParams: c.typeParams(typeParams, bounds)}
+	prev := c.env
+	c.env = typeParamsEnv(prev, fn.TypeParams)
+	fn.Return = c.resolve(ret)
+	for _, v := range params {
+		fn.Params = append(fn.Params, c.resolve(v))
+	}
+	c.env = prev
+	return fn
+}
+
+// instantiate gives every one of params the type subst has for it, or Any
+func instantiate(params []*TypeParam, subst map[*TypeParam]Type) map[*TypeParam]Type {
+	result := make(map[*TypeParam]Type)
+	for _, v := range params {
+		result[v] = Any
+		if val, ok := subst[v]; ok {
+			result[v] = val
+		}
+	}
+	return result
+}
+
+// resolve turns an annotation into a Type, a missing annotation is Any
+func (c *checker) resolve(typ syntaxtree.TypeExpr) Type {
+	switch typ := typ.(type) {
+	case syntaxtree.FunctionType:
+		return c.signature(nil, nil, typ.Params, typ.Return)
+	case syntaxtree.NamedType:
+		return c.resolveNamed(typ)
+	}
+	return Any
+}
+
+func (c *checker) resolveNamed(typ syntaxtree.NamedType) Type {
+	var args []Type
+	for _, v := range typ.Arguments {
+		args = append(args, c.resolve(v))
+	}
+	arity := func(n int) bool {
+		if len(args) != 0 && len(args) != n {
+			c.error(typ.Name, typ.Name.Content+" expects "+strconv.Itoa(n)+" type arguments but got "+strconv.Itoa(len(args)))
+			return false
+		}
+		return len(args) == n
+	}
+	switch typ.Name.Content {
+	case "Any", "Int", "Float", "String", "Bool", "Nil":
+		arity(0)
+		return Basic{name: typ.Name.Content}
+	case "List":
+		if arity(1) {
+			return List{Elem: args[0]}
+		}
+		return List{Elem: Any}
+	case "Map":
+		if arity(2) {
+			return Map{Key: args[0], Value: args[1]}
+		}
+		return Map{Key: Any, Value: Any}
+	}
+	if val, ok := c.env.lookupType(typ.Name.Content); ok {
+		structType, ok := val.(*Struct)
+		if !ok || structType.TypeParams == nil {
+			arity(0)
+			return val
+		}
+		applied := Applied{Struct: structType}
+		if arity(len(structType.TypeParams)) {
+			applied.Args = args
+			_, subst, _ := structOf(applied)
+			check := func() { c.bounded(typ.Name, structType.TypeParams, subst) }
+			if c.pending != nil {
+				c.pending = append(c.pending, check)
+			} else {
+				check()
+			}
+		} else {
+			for range structType.TypeParams {
+				applied.Args = append(applied.Args, Any)
+			}
+		}
+		return applied
+	}
+	c.error(typ.Name, "unknown type "+typ.Name.Content)
+	return Any
+}
+
+// function checks a fn body against the signature of the fn, without a return annotation
+// the return type is inferred from the return statements of the body
+func (c *checker) function(fn *Func, params []tokenizer.Token, body []syntaxtree.Stmt, self Type, infer bool) *Func {
+	prevEnv, prevReturns, prevReturned := c.env, c.returns, c.returned
+	c.env = newEnv(typeParamsEnv(c.env, fn.TypeParams))
+	if self != nil {
+		c.env.vars["self"] = self
+	}
+	for i, v := range params {
+		c.env.vars[v.Content] = fn.Params[i]
+	}
+	c.returns, c.returned = fn.Return, nil
+	if infer {
+		c.returns = nil
+	}
+	c.block(body)
+	if infer {
+		if !terminates(body) {
+			c.returned = append(c.returned, Nil)
+		}
+		fn.Return = c.returned[0]
+		for _, v := range c.returned[1:] {
+			fn.Return = join(fn.Return, v)
+		}
+	}
+	c.env, c.returns, c.returned = prevEnv, prevReturns, prevReturned
+	return fn
+}
+
+// terminates reports whether running the statements always ends in a return
+func terminates(stmt []syntaxtree.Stmt) bool {
+	if len(stmt) == 0 {
+		return false
+	}
+	switch last := stmt[len(stmt)-1].(type) {
+	case syntaxtree.ReturnStmt:
+		return true
+	case syntaxtree.BlockStmt:
+		return terminates(last.Statements)
+	case syntaxtree.IfStmt:
+		return last.Else != nil && terminates([]syntaxtree.Stmt{last.Block}) && terminates([]syntaxtree.Stmt{last.Else})
+	}
+	return false
+}
+
+// ordered reports whether values of type t compare with < through a less method
+func ordered(t Type) bool {
+	_, ok := methodOf(t, "less")
+	return ok
+}
+
+// operation is the type of left op right, reporting operands the interpreter would reject
+func (c *checker) operation(op tokenizer.Token, left Type, right Type) Type {
+	if left == Any || right == Any {
+		switch op.Type {
+		case tokenizer.LESS, tokenizer.GREATER, tokenizer.LESS_EQUAL, tokenizer.GREATER_EQUAL:
+			return Bool
+		}
+		return Any
+	}
+	numbers := isNumber(left) && isNumber(right)
+	switch op.Type {
+	case tokenizer.PLUS:
+		if numbers {
+			return join(left, right)
+		}
+		if left == String && right == String {
+			return String
+		}
+		if left, ok := left.(List); ok {
+			if right, ok := right.(List); ok {
+				return List{Elem: join(left.Elem, right.Elem)}
+			}
+		}
+	case tokenizer.MINUS, tokenizer.STAR, tokenizer.SLASH, tokenizer.PERCENT, tokenizer.STAR_STAR:
+		if numbers {
+			return join(left, right)
+		}
+	case tokenizer.LESS, tokenizer.GREATER, tokenizer.LESS_EQUAL, tokenizer.GREATER_EQUAL:
+		if numbers || left == String && right == String || ordered(left) && ordered(right) {
+			return Bool
+		}
+	case tokenizer.PIPE, tokenizer.AMPERSAND, tokenizer.CARET:
+		if left == Int && right == Int {
+			return Int
+		}
+		if left == Bool && right == Bool {
+			return Bool
+		}
+	case tokenizer.LESS_LESS, tokenizer.GREATER_GREATER:
+		if left == Int && right == Int {
+			return Int
+		}
+	}
+	c.error(op, "unsupported operation of ("+left.String()+" and "+right.String()+")")
+	return Any
+}
+
+func (c *checker) VisitBinaryExpr(expr syntaxtree.BinaryExpr) Type {
+	if _, ok := compoundOperators[expr.Operator.Type]; ok || expr.Operator.Type == tokenizer.EQUAL {
+		return c.assign(expr)
+	}
+	left := c.expr(expr.Left)
+	right := c.expr(expr.Right)
+	switch expr.Operator.Type {
+	case tokenizer.EQUAL_EQUAL, tokenizer.EXCLAMATION_EQUAL:
+		return Bool
+	case tokenizer.IN:
+		switch right.(type) {
+		case List, Map:
+		default:
+			if right != Any {
+				c.error(expr.Operator, "cannot check membership in "+right.String())
+			}
+		}
+		return Bool
+	}
+	result := c.operation(expr.Operator, left, right)
+	// an Int to a negative power is a Float, so the type is only known when the sign of the exponent is
+	if expr.Operator.Type == tokenizer.STAR_STAR && result == Int {
+		if nonNegative(expr.Right) {
+			return Int
+		}
+		if negative(expr.Right) {
+			return Float
+		}
+		return Any
+	}
+	return result
+}
+
+// nonNegative reports whether expr is a number literal, a minus in front of one is a separate unary expression
+func nonNegative(expr syntaxtree.Expr) bool {
+	switch expr := expr.(type) {
+	case syntaxtree.LiteralExpr:
+		return expr.Value.Type == tokenizer.NUMBER
+	case syntaxtree.GroupingExpr:
+		return nonNegative(expr.Inside)
+	}
+	return false
+}
+
+// negative reports whether expr is a minus in front of an Int literal other than 0
+func negative(expr syntaxtree.Expr) bool {
+	switch expr := expr.(type) {
+	case syntaxtree.UnaryExpr:
+		literal, ok := expr.Right.(syntaxtree.LiteralExpr)
+		if !ok || expr.Operator.Type != tokenizer.MINUS || literal.Value.Type != tokenizer.NUMBER {
+			return false
+		}
+		val, err := tokenizer.ParseInt(literal.Value.Content)
+		return err == nil && val > 0
+	case syntaxtree.GroupingExpr:
+		return negative(expr.Inside)
+	}
+	return false
+}
+
+// compound applies the operator of a compound assignment to the current value of the target
+func (c *checker) compound(op tokenizer.Token, current Type, value Type) Type {
+	if operator, ok := compoundOperators[op.Type]; ok {
+		op.Type = operator
+		return c.operation(op, current, value)
+	}
+	return value
+}
+
+func (c *checker) assign(expr syntaxtree.BinaryExpr) Type {
+	name := expr.Left.(syntaxtree.LiteralExpr).Value.Content
+	if keyword, ok := c.env.lookupImmutable(name); ok {
+		c.error(expr.Left.(syntaxtree.LiteralExpr).Value, "cannot assign to "+keyword.Content+" "+name)
+	}
+	target := c.env.lookup(name)
+	value := c.compound(expr.Operator, target, c.expr(expr.Right))
+	if assignable(target, value) {
+		return target
+	}
+	// a var without an annotation widens to fit the values assigned to it, as long as they have a common type
+	if scope, ok := c.env.lookupInferred(name); ok && join(target, value) != Any {
+		scope.vars[name] = join(target, value)
+		c.declarations[scope.inferred[name]].Type = scope.vars[name]
+		return scope.vars[name]
+	}
+	c.error(expr.Operator, "cannot assign "+value.String()+" to "+name+" of type "+target.String())
+	return target
+}
+
+func (c *checker) VisitConditionalExpr(expr syntaxtree.ConditionalExpr) Type {
+	c.expr(expr.Condition)
+	return join(c.expr(expr.Then), c.expr(expr.Else))
+}
+
+func (c *checker) VisitLogicalExpr(expr syntaxtree.LogicalExpr) Type {
+	return join(c.expr(expr.Left), c.expr(expr.Right))
+}
+
+func (c *checker) VisitUnaryExpr(expr syntaxtree.UnaryExpr) Type {
+	right := c.expr(expr.Right)
+	if right == Any {
+		if expr.Operator.Type == tokenizer.EXCLAMATION {
+			return Bool
+		}
+		return Any
+	}
+	switch expr.Operator.Type {
+	case tokenizer.EXCLAMATION:
+		return Bool
+	case tokenizer.MINUS:
+		if isNumber(right) {
+			return right
+		}
+	case tokenizer.TILDE:
+		if right == Int {
+			return Int
+		}
+	}
+	c.error(expr.Operator, "unsupported operation of ("+right.String()+" and "+right.String()+")")
+	return Any
+}
+
+func (c *checker) VisitGroupingExpr(expr syntaxtree.GroupingExpr) Type {
+	return c.expr(expr.Inside)
+}
+
+func (c *checker) VisitLiteralExpr(expr syntaxtree.LiteralExpr) Type {
+	switch expr.Value.Type {
+	case tokenizer.NUMBER:
+		if tokenizer.IsFloat(expr.Value.Content) {
+			return Float
+		}
+		return Int
+	case tokenizer.STRING:
+		return String
+	case tokenizer.TRUE, tokenizer.FALSE:
+		return Bool
+	case tokenizer.NIL:
+		return Nil
+	case tokenizer.IDENTIFIER:
+		return c.env.lookup(expr.Value.Content)
+	}
+	return Any
+}
+
+func (c *checker) VisitInterpolatedStringExpr(expr syntaxtree.InterpolatedStringExpr) Type {
+	for _, v := range expr.Parts {
+		c.expr(v)
+	}
+	return String
+}
+
+func (c *checker) VisitFunctionExpr(expr syntaxtree.FunctionExpr) Type {
+	return c.lambda(expr, nil)
+}
+
+func (c *checker) lambda(expr syntaxtree.FunctionExpr, expected Type) Type {
+	fn := c.signature(nil, nil, expr.ParamTypes, expr.Return)
+	if expected, ok := expected.(*Func); ok && len(expected.Params) == len(fn.Params) {
+		for i, v := range expr.ParamTypes {
+			if v == nil {
+				fn.Params[i] = expected.Params[i]
+			}
+		}
+	}
+	return c.function(fn, expr.Params, expr.Body, nil, expr.Return == nil)
+}
+
+func (c *checker) VisitCallExpr(expr syntaxtree.CallExpr) Type {
+	callee := c.expr(expr.Calle)
+	fn, ok := callee.(*Func)
+	// type parameters of a generic fn are bound argument by argument, so that a lambda
+	// argument sees the types the arguments before it settled on
+	subst := make(map[*TypeParam]Type)
+	var args []Type
+	for i, v := range expr.Arguments {
+		if !ok || i >= len(fn.Params) {
+			args = append(args, c.expr(v))
+			continue
+		}
+		arg := c.exprExpecting(v, substitute(fn.Params[i], instantiate(fn.TypeParams, subst)))
+		unify(fn.Params[i], arg, subst)
+		args = append(args, arg)
+	}
+	if callee == Any {
+		return Any
+	}
+	if !ok {
+		c.error(expr.Paren, "cannot call "+callee.String())
+		return Any
+	}
+	name := calleeName(expr.Calle)
+	subst = instantiate(fn.TypeParams, subst)
+	if len(args) != len(fn.Params) {
+		c.error(expr.Paren, name+" expected "+strconv.Itoa(len(fn.Params))+" arguments but got "+strconv.Itoa(len(args)))
+		return substitute(fn.Return, subst)
+	}
+	for i, v := range args {
+		if param := substitute(fn.Params[i], subst); !assignable(param, v) {
+			c.error(expr.Paren, "cannot use "+v.String()+" as "+param.String()+" in argument "+strconv.Itoa(i+1)+" of "+name)
+		}
+	}
+	c.bounded(expr.Paren, fn.TypeParams, subst)
+	return substitute(fn.Return, subst)
+}
+
+func calleeName(expr syntaxtree.Expr) string {
+	switch expr := expr.(type) {
+	case syntaxtree.LiteralExpr:
+		return expr.Value.Content
+	case syntaxtree.GetExpr:
+		return calleeName(expr.Object) + "." + expr.Name.Content
+	}
+	return "fn"
+}
+
+func (c *checker) VisitGetExpr(expr syntaxtree.GetExpr) Type {
+	object := c.expr(expr.Object)
+	name := expr.Name.Content
+	if structType, subst, ok := structOf(object); ok {
+		if val, ok := structType.FieldTypes[name]; ok {
+			return substitute(val, subst)
+		}
+		if val, ok := structType.Methods[name]; ok {
+			return substitute(val, subst)
+		}
+		c.error(expr.Name, structType.Name+" has no field "+name)
+		return Any
+	}
+	switch object := object.(type) {
+	case *Trait:
+		if val, ok := object.Types[name]; ok {
+			return val
+		}
+		c.error(expr.Name, object.Name+" has no method "+name)
+		return Any
+	case EnumObject:
+		if !contains(object.Enum.Variants, name) {
+			c.error(expr.Name, object.Enum.Name+" has no variant "+name)
+			return Any
+		}
+		if types := object.Enum.Types[name]; len(types) != 0 {
+			return &Func{Params: types, Return: object.Enum}
+		}
+		return object.Enum
+	case *Enum:
+		return Any
+	case *TypeParam:
+		if method, ok := methodOf(object, name); ok {
+			return method
+		}
+	}
+	if object != Any {
+		c.error(expr.Name, "cannot get field "+name+" of "+object.String())
+	}
+	return Any
+}
+
+func contains(list []string, str string) bool {
+	for _, v := range list {
+		if v == str {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) VisitSetExpr(expr syntaxtree.SetExpr) Type {
+	object := c.expr(expr.Object)
+	value := c.expr(expr.Value)
+	name := expr.Name.Content
+	structType, subst, ok := structOf(object)
+	if !ok {
+		if object != Any {
+			c.error(expr.Name, "cannot set field "+name+" of "+object.String())
+		}
+		return Any
+	}
+	target, ok := structType.FieldTypes[name]
+	if !ok {
+		c.error(expr.Name, structType.Name+" has no field "+name)
+		return Any
+	}
+	target = substitute(target, subst)
+	value = c.compound(expr.Operator, target, value)
+	if !assignable(target, value) {
+		c.error(expr.Name, "cannot assign "+value.String()+" to field "+name+" of type "+target.String())
+	}
+	return target
+}
+
+func (c *checker) VisitListExpr(expr syntaxtree.ListExpr) Type {
+	var elem Type
+	for _, v := range expr.Elements {
+		if val := c.expr(v); elem == nil {
+			elem = val
+		} else {
+			elem = join(elem, val)
+		}
+	}
+	if elem == nil {
+		elem = Any
+	}
+	return List{Elem: elem}
+}
+
+func (c *checker) VisitMapExpr(expr syntaxtree.MapExpr) Type {
+	var key, value Type = Any, Any
+	for i := range expr.Keys {
+		k := c.expr(expr.Keys[i])
+		v := c.expr(expr.Values[i])
+		c.hashable(expr.Brace, k)
+		if i == 0 {
+			key, value = k, v
+		} else {
+			key, value = join(key, k), join(value, v)
+		}
+	}
+	return Map{Key: key, Value: value}
+}
+
+func (c *checker) hashable(token tokenizer.Token, typ Type) {
+	switch typ.(type) {
+	case List, Map, *Func:
+		c.error(token, "unhashable key type "+typ.String())
+	}
+}
+
+// element is the type of object[index]
+func (c *checker) element(bracket tokenizer.Token, object Type, index Type) Type {
+	switch object := object.(type) {
+	case List:
+		if !assignable(Int, index) {
+			c.error(bracket, "list index must be Int, got "+index.String())
+		}
+		return object.Elem
+	case Map:
+		c.hashable(bracket, index)
+		if !assignable(object.Key, index) {
+			c.error(bracket, "cannot use "+index.String()+" as key of "+object.String())
+		}
+		return object.Value
+	}
+	if object != Any {
+		c.error(bracket, "cannot index "+object.String())
+	}
+	return Any
+}
+
+func (c *checker) VisitIndexExpr(expr syntaxtree.IndexExpr) Type {
+	object := c.expr(expr.Object)
+	return c.element(expr.Bracket, object, c.expr(expr.Index))
+}
+
+func (c *checker) VisitIndexSetExpr(expr syntaxtree.IndexSetExpr) Type {
+	object := c.expr(expr.Object)
+	target := c.element(expr.Bracket, object, c.expr(expr.Index))
+	value := c.compound(expr.Operator, target, c.expr(expr.Value))
+	if !assignable(target, value) {
+		c.error(expr.Bracket, "cannot assign "+value.String()+" to element of "+object.String())
+	}
+	return target
+}
+
+func (c *checker) VisitSliceExpr(expr syntaxtree.SliceExpr) Type {
+	object := c.expr(expr.Object)
+	for _, v := range []syntaxtree.Expr{expr.Start, expr.End} {
+		if v == nil {
+			continue
+		}
+		if bound := c.expr(v); !assignable(Int, bound) {
+			c.error(expr.Bracket, "slice bound must be Int, got "+bound.String())
+		}
+	}
+	switch object.(type) {
+	case List:
+		return object
+	}
+	if object != Any {
+		c.error(expr.Bracket, "cannot slice "+object.String())
+	}
+	return Any
+}
+
+func (c *checker) VisitExpressionStmt(stmt syntaxtree.ExpressionStmt) any {
+	c.expr(stmt.Expression)
+	return nil
+}
+
+func (c *checker) VisitPrintStmt(stmt syntaxtree.PrintStmt) any {
+	c.expr(stmt.Expression)
+	return nil
+}
+
+func (c *checker) VisitBlockStmt(stmt syntaxtree.BlockStmt) any {
+	prev := c.env
+	c.env = newEnv(prev)
+	c.block(stmt.Statements)
+	c.env = prev
+	return nil
+}
+
+func (c *checker) VisitIfStmt(stmt syntaxtree.IfStmt) any {
+	c.expr(stmt.Condition)
+	c.stmt(stmt.Block)
+	if stmt.Else != nil {
+		c.stmt(stmt.Else)
+	}
+	return nil
+}
+
+func (c *checker) VisitVarDeclStmt(stmt syntaxtree.VarDeclStmt) any {
+	if keyword, ok := c.env.immutable[stmt.Name.Content]; ok {
+		c.error(stmt.Name, "cannot redeclare "+keyword.Content+" "+stmt.Name.Content)
+	}
+	if stmt.Keyword.Type == tokenizer.CONST && !c.constant(stmt.Expression) {
+		c.error(stmt.Name, "const "+stmt.Name.Content+" needs a constant value")
+	}
+	declared := c.resolve(stmt.Type)
+	value := c.exprExpecting(stmt.Expression, declared)
+	if !assignable(declared, value) {
+		c.error(stmt.Name, "cannot assign "+value.String()+" to "+stmt.Name.Content+" of type "+declared.String())
+	}
+	// without an annotation the var takes the type of its initializer, nil says nothing about later values
+	if stmt.Type == nil && value != Nil {
+		declared = value
+	}
+	c.env.vars[stmt.Name.Content] = declared
+	if stmt.Keyword.Type != tokenizer.VAR {
+		c.env.immutable[stmt.Name.Content] = stmt.Keyword
+	}
+	delete(c.env.inferred, stmt.Name.Content)
+	if stmt.Type == nil {
+		c.env.inferred[stmt.Name.Content] = len(c.declarations)
+	}
+	c.declared(stmt.Name, declared)
+	return nil
+}
+
+// constant reports whether expr is built from literals and consts only, so that it can be folded before running
+func (c *checker) constant(expr syntaxtree.Expr) bool {
+	switch expr := expr.(type) {
+	case syntaxtree.LiteralExpr:
+		if expr.Value.Type != tokenizer.IDENTIFIER {
+			return true
+		}
+		keyword, ok := c.env.lookupImmutable(expr.Value.Content)
+		return ok && keyword.Type == tokenizer.CONST
+	case syntaxtree.GroupingExpr:
+		return c.constant(expr.Inside)
+	case syntaxtree.UnaryExpr:
+		return c.constant(expr.Right)
+	case syntaxtree.BinaryExpr:
+		if _, ok := compoundOperators[expr.Operator.Type]; ok || expr.Operator.Type == tokenizer.EQUAL {
+			return false
+		}
+		return c.constant(expr.Left) && c.constant(expr.Right)
+	case syntaxtree.LogicalExpr:
+		return c.constant(expr.Left) && c.constant(expr.Right)
+	case syntaxtree.ConditionalExpr:
+		return c.constant(expr.Condition) && c.constant(expr.Then) && c.constant(expr.Else)
+	case syntaxtree.InterpolatedStringExpr:
+		for _, v := range expr.Parts {
+			if !c.constant(v) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (c *checker) VisitForStmt(stmt syntaxtree.ForStmt) any {
+	prev := c.env
+	c.env = newEnv(prev)
+	c.stmt(stmt.PreStatement)
+	c.expr(stmt.Condition)
+	c.expr(stmt.PostStatement)
+	c.stmt(stmt.Block)
+	c.env = prev
+	return nil
+}
+
+func (c *checker) VisitFuncDeclStmt(stmt syntaxtree.FuncDeclStmt) any {
+	// the signature was resolved when the block was declared, unless a var of the same name replaced it
+	fn, ok := c.env.vars[stmt.Name.Content].(*Func)
+	if !ok {
+		fn = c.signature(stmt.TypeParams, stmt.Bounds, stmt.ParamTypes, stmt.Return)
+	}
+	c.function(fn, stmt.Params, stmt.Body, nil, stmt.Return == nil)
+	c.declared(stmt.Name, fn)
+	return nil
+}
+
+func (c *checker) VisitStructDeclStmt(stmt syntaxtree.StructDeclStmt) any {
+	structType, ok := c.env.types[stmt.Name.Content].(*Struct)
+	if !ok {
+		return nil
+	}
+	prev := c.env
+	c.env = typeParamsEnv(prev, structType.TypeParams)
+	for _, v := range stmt.Methods {
+		method := c.function(structType.Methods[v.Name.Content], v.Params, v.Body, selfType(structType), v.Return == nil)
+		c.declared(tokenizer.Token{Content: stmt.Name.Content + "." + v.Name.Content, Line: v.Name.Line}, method)
+	}
+	c.env = prev
+	// conformance is checked once the return types of the methods are inferred
+	for _, v := range stmt.Traits {
+		val, ok := c.env.lookupType(v.Content)
+		trait, isTrait := val.(*Trait)
+		if !ok {
+			c.error(v, "unknown trait "+v.Content)
+		} else if !isTrait {
+			c.error(v, v.Content+" is not a trait")
+		} else if reason := conforms(selfType(structType), trait); reason != "" {
+			c.error(v, structType.Name+" does not implement "+trait.Name+", "+reason)
+		}
+	}
+	return nil
+}
+
+func (c *checker) VisitTraitDeclStmt(stmt syntaxtree.TraitDeclStmt) any {
+	trait, ok := c.env.types[stmt.Name.Content].(*Trait)
+	if !ok {
+		return nil
+	}
+	for _, v := range stmt.Defaults {
+		method := c.function(trait.Types[v.Name.Content], v.Params, v.Body, trait, v.Return == nil)
+		c.declared(tokenizer.Token{Content: stmt.Name.Content + "." + v.Name.Content, Line: v.Name.Line}, method)
+	}
+	return nil
+}
+
+func (c *checker) VisitEnumDeclStmt(stmt syntaxtree.EnumDeclStmt) any {
+	return nil
+}
+
+func (c *checker) VisitReturnStmt(stmt syntaxtree.ReturnStmt) any {
+	var value Type = Nil
+	if stmt.Value != nil {
+		value = c.exprExpecting(stmt.Value, c.returns)
+	}
+	c.returned = append(c.returned, value)
+	if c.returns != nil && !assignable(c.returns, value) {
+		c.error(stmt.Keyword, "cannot return "+value.String()+" from fn returning "+c.returns.String())
+	}
+	return nil
+}
+
+func (c *checker) VisitWhileStmt(stmt syntaxtree.WhileStmt) any {
+	c.expr(stmt.Condition)
+	c.stmt(stmt.Block)
+	return nil
+}
+
+func (c *checker) VisitBreakStmt(stmt syntaxtree.BreakStmt) any {
+	return nil
+}
+
+func (c *checker) VisitContinueStmt(stmt syntaxtree.ContinueStmt) any {
+	return nil
+}
+
+func (c *checker) VisitMatchStmt(stmt syntaxtree.MatchStmt) any {
+	value := c.expr(stmt.Value)
+	for i, v := range stmt.Patterns {
+		prev := c.env
+		c.env = newEnv(prev)
+		c.pattern(v, value)
+		if stmt.Guards[i] != nil {
+			c.expr(stmt.Guards[i])
+		}
+		c.stmt(stmt.Bodies[i])
+		c.env = prev
+	}
+	return nil
+}
+
+// pattern declares the variables a pattern binds when it matches a value of type typ
+func (c *checker) pattern(pattern syntaxtree.Pattern, typ Type) {
+	switch pattern := pattern.(type) {
+	case syntaxtree.BindingPattern:
+		c.env.vars[pattern.Name.Content] = typ
+	case syntaxtree.AlternativePattern:
+		for _, v := range pattern.Alternatives {
+			c.pattern(v, typ)
+		}
+	case syntaxtree.ListPattern:
+		elem := Type(Any)
+		if list, ok := typ.(List); ok {
+			elem = list.Elem
+		} else if typ != Any {
+			c.error(pattern.Bracket, "list pattern cannot match "+typ.String())
+		}
+		for _, v := range pattern.Elements {
+			c.pattern(v, elem)
+		}
+	case syntaxtree.StructPattern:
+		val, _ := c.env.lookupType(pattern.Name.Content)
+		structType, ok := val.(*Struct)
+		if !ok {
+			c.error(pattern.Name, "unknown struct "+pattern.Name.Content)
+			return
+		}
+		// the type parameters of a generic struct are only known when the matched value is of that struct
+		subst := instantiate(structType.TypeParams, nil)
+		if matched, known, ok := structOf(typ); ok && matched == structType && known != nil {
+			subst = known
+		}
+		for i, v := range pattern.Fields {
+			field, ok := structType.FieldTypes[v.Content]
+			if !ok {
+				c.error(v, structType.Name+" has no field "+v.Content)
+				field = Any
+			}
+			c.pattern(pattern.Patterns[i], substitute(field, subst))
+		}
+	case syntaxtree.EnumPattern:
+		val, _ := c.env.lookupType(pattern.Enum.Content)
+		enum, ok := val.(*Enum)
+		if !ok {
+			c.error(pattern.Enum, "unknown enum "+pattern.Enum.Content)
+			return
+		}
+		if !contains(enum.Variants, pattern.Variant.Content) {
+			c.error(pattern.Variant, enum.Name+" has no variant "+pattern.Variant.Content)
+			return
+		}
+		types := enum.Types[pattern.Variant.Content]
+		if pattern.Patterns != nil && len(pattern.Patterns) != len(types) {
+			c.error(pattern.Variant, enum.Name+"."+pattern.Variant.Content+" has "+strconv.Itoa(len(types))+" fields but the pattern has "+strconv.Itoa(len(pattern.Patterns)))
+			return
+		}
+		for i, v := range pattern.Patterns {
+			c.pattern(v, types[i])
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/WhoDoIt/GoCompiler/internal/parser"
+	"github.com/WhoDoIt/GoCompiler/internal/tokenizer"
+)
+
+func check(t *testing.T, source string) ([]Declaration, []string) {
+	tokens, err := tokenizer.Tokenize([]byte(source))
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	tree, err := parser.Parse(tokens)
+	if err != nil {
+		t.Fatalf("%s: %v", source, err)
+	}
+	declarations, errs := Check(tree)
+	var messages []string
+	for _, v := range errs {
+		messages = append(messages, v.Error())
+	}
+	return declarations, messages
+}
+
+// TestErrors checks every program against the exact errors it gets, a program without errors has none
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		source string
+		errors []string
+	}{
+		{"var x: Int = 1; var y: Float = x;", nil},
+		{"var x: Int = \"a\";", []string{"[cannot assign String to x of type Int] on line 1"}},
+		{"var x: Nope = 1;", []string{"[unknown type Nope] on line 1"}},
+		{"var x: List[Int, Int] = [];", []string{"[List expects 1 type arguments but got 2] on line 1"}},
+		{"fn f(a: Int) -> String { return a; }", []string{"[cannot return Int from fn returning String] on line 1"}},
+		{"fn f(a: Int) {} f(\"a\");", []string{"[cannot use String as Int in argument 1 of f] on line 1"}},
+		{"fn f(a) {} f(1, 2);", []string{"[f expected 1 arguments but got 2] on line 1"}},
+		{"var n: Int = 1; n();", []string{"[cannot call Int] on line 1"}},
+		{"print 1 + \"a\";", []string{"[unsupported operation of (Int and String)] on line 1"}},
+		{"print [1][\"a\"];", []string{"[list index must be Int, got String] on line 1"}},
+		{"print {[1]: 1};", []string{"[unhashable key type List[Int]] on line 1"}},
+		{"print 1 in 2;", []string{"[cannot check membership in Int] on line 1"}},
+		{"struct P { x: Int } print P(1).z;", []string{"[P has no field z] on line 1"}},
+		{"enum Shape { Circle(r: Float) } print Shape.Square;", []string{"[Shape has no variant Square] on line 1"}},
+		{"fn f(a, b) { return a + b; } f(1, \"a\");", nil},
+		{"var i = 3; i = nil; i = 4;", nil},
+		{"var i = 3; i = \"a\";", []string{"[cannot assign String to i of type Int] on line 1"}},
+		{"fn f() { return 1; } var s: String = f();", []string{"[cannot assign Int to s of type String] on line 1"}},
+		{"var f: fn(Int) -> Int = (x) => x * 2;", nil},
+		{"fn id[T](x: T) -> T { return x; } var s: String = id(1);", []string{"[cannot assign Int to s of type String] on line 1"}},
+		{"fn first[T](xs: List[T]) -> T { return xs[0]; } var n: Int = first([1]);", nil},
+		{"struct Box[T] { value: T } var b: Box[String] = Box(1);", []string{"[cannot assign Box[Int] to b of type Box[String]] on line 1"}},
+		{"struct Box[T] { value: T } var b: Box = Box(1); var c: Box[Int, Int] = b;", []string{"[Box expects 1 type arguments but got 2] on line 1"}},
+		{"var xs = [1]; push(xs, \"a\");", []string{"[cannot use String as Int in argument 2 of push] on line 1"}},
+		{"struct P { x: Int } var p = P(1); p.x = \"a\";", []string{"[cannot assign String to field x of type Int] on line 1"}},
+		{"var s: String = nil;", nil},
+		{"var f: fn(Int) -> Int = fn(x: Int) -> Int { return x; }; var g: fn(String) -> Int = f;", []string{"[cannot assign fn(Int) -> Int to g of type fn(String) -> Int] on line 1"}},
+		{"trait Shape { fn area() -> Float; } struct Sq : Shape { fn area() -> Float { return 1.0; } }", nil},
+		{"trait Shape { fn area() -> Float; } struct Sq : Shape { }", []string{"[Sq does not implement Shape, missing method area] on line 1"}},
+		{"trait Shape { fn area() -> Float; } struct Sq : Shape { fn area() -> String { return \"\"; } }", []string{"[Sq does not implement Shape, method area is fn() -> String but Shape needs fn() -> Float] on line 1"}},
+		{"struct Sq : Nope { }", []string{"[unknown trait Nope] on line 1"}},
+		{"trait Shape { fn area() -> Float; } struct Sq { } var s: Shape = Sq();", []string{"[cannot assign Sq to s of type Shape] on line 1"}},
+		{"trait Shape { fn area() -> Float; fn twice() -> Float { return self.area() * 2; } } struct Sq : Shape { fn area() -> Float { return 1.0; } } var n: Float = Sq().twice();", nil},
+		{"struct M { n: Int fn less(other: M) -> Bool { return self.n < other.n; } } print M(1) < M(2);", nil},
+		{"struct M { n: Int } print M(1) < M(2);", []string{"[unsupported operation of (M and M)] on line 1"}},
+		{"const N = 1; N = 2;", []string{"[cannot assign to const N] on line 1"}},
+		{"let x = 1; x++;", []string{"[cannot assign to let x] on line 1"}},
+		{"let x = 1; fn f() { x = 2; }", []string{"[cannot assign to let x] on line 1"}},
+		{"let x = 1; { var x = 2; x = 3; } fn f(x) { x = 4; }", nil},
+		{"const N = len([]);", []string{"[const N needs a constant value] on line 1"}},
+		{"const N = 2; const M = N * 3 + 1; const S = \"${M}\";", nil},
+		{"const N = 1; let N = 2;", []string{"[cannot redeclare const N] on line 1"}},
+		{"struct A { x } enum A { B }", []string{"[A already declared in this scope] on line 1"}},
+		{"enum A { B } struct A { x }", []string{"[A already declared in this scope] on line 1"}},
+		{"struct A { x } struct A { y } print A(1).x;", []string{"[A already declared in this scope] on line 1"}},
+		{"fn f() {} fn f(a) {} f();", []string{"[f already declared in this scope] on line 1"}},
+		{"struct A { x } fn A() {}", []string{"[A already declared in this scope] on line 1"}},
+		{"struct A { x } { struct A { y } print A(1).y; }", nil},
+		{"trait A { fn f(); } struct A { x }", []string{"[A already declared in this scope] on line 1"}},
+		{"struct A { x } trait A { fn f(); }", []string{"[A already declared in this scope] on line 1"}},
+		{"trait A { fn f(); } trait A { fn g(); } struct B : A { fn f() {} }", []string{"[A already declared in this scope] on line 1"}},
+		{"print true & false; print true | false; print true ^ true; var b: Bool = 1 < 2 & true;", nil},
+		{"print 6 & 3 | 1 ^ 2 << 1;", nil},
+		{"print true & 1;", []string{"[unsupported operation of (Bool and Int)] on line 1"}},
+		{"print true << true;", []string{"[unsupported operation of (Bool and Bool)] on line 1"}},
+		{"var y: Int = 2 ** 10; var z: Int = 2 ** (3); var f: Float = 2.0 ** -1;", nil},
+		{"var y: Int = 2 ** -1;", []string{"[cannot assign Float to y of type Int] on line 1"}},
+		{"var n = -1; var x = 2 ** n; var s: String = x; var z: Int = 2 ** -0;", nil},
+		{"var y: String = 2 ** 10;", []string{"[cannot assign Int to y of type String] on line 1"}},
+		{"fn add[T](x: T) -> T { return x + 1; }", []string{"[unsupported operation of (T and Int)] on line 1"}},
+		{"fn neg[T](x: T) { return -x; }", []string{"[unsupported operation of (T and T)] on line 1"}},
+		{"fn less[T](a: T, b: T) { return a < b; }", []string{"[unsupported operation of (T and T)] on line 1"}},
+		{"fn get[T](x: T) { return x.name; }", []string{"[cannot get field name of T] on line 1"}},
+		{"fn set[T](x: T) { x.name = 1; }", []string{"[cannot set field name of T] on line 1"}},
+		{"fn call[T](x: T) { return x(); }", []string{"[cannot call T] on line 1"}},
+		{"fn at[T](x: T) { return x[0]; }", []string{"[cannot index T] on line 1"}},
+		{"fn has[T](x: T, xs: List[T]) { return x == xs[0] and x in xs and !x; }", nil},
+		{"struct Box[T] { value: T fn bump() { return self.value + 1; } }", []string{"[unsupported operation of (T and Int)] on line 1"}},
+		{"fn max[T: Ord](a: T, b: T) -> T { return a < b ? b : a; } fn label[T: Display](x: T) -> String { return x.show(); }", nil},
+		{"fn label[T: Display](x: T) { return x.area(); }", []string{"[cannot get field area of T] on line 1"}},
+		{"fn f[T: Display](x: T) { var d: Display = x; } fn g[T](x: T) { var d: Display = x; }", []string{"[cannot assign T to d of type Display] on line 1"}},
+		{"fn f[T: Nope](x: T) {}", []string{"[unknown trait Nope] on line 1"}},
+		{"struct P { x: Int } fn f[T: Display](x: T) {} f(1); f(P(1));", []string{"[Int does not implement Display, missing method show] on line 1", "[P does not implement Display, missing method show] on line 1"}},
+		{"struct P { fn show() -> String { return \"p\"; } } fn f[T: Display + Eq](x: T) {} f(P());", []string{"[P does not implement Eq, missing method eq] on line 1"}},
+		{"fn f[T: Display](x: T) {} fn g[T](x: T) { f(x); } fn h[T: Display](x: T) { f(x); }", []string{"[T does not implement Display, missing method show] on line 1"}},
+		{"struct Box[T: Display] { value: T } var b = Box(1); fn f(b: Box[Int]) {}", []string{"[Int does not implement Display, missing method show] on line 1", "[Int does not implement Display, missing method show] on line 1"}},
+		{"var b: Box[Q] = nil; struct Box[T: Display] { value: T fn show() -> String { return self.value.show(); } } struct Q { fn show() -> String { return \"q\"; } }", nil},
+		{"var i = 0; i += 0.5; var f: Float = i; var xs = [1]; xs = [2.5];", nil},
+		{"var i = 0; { i = 0.5; } var n: Int = i;", []string{"[cannot assign Float to n of type Int] on line 1"}},
+		{"var i: Int = 0; i += 0.5;", []string{"[cannot assign Float to i of type Int] on line 1"}},
+	}
+	for _, test := range tests {
+		if _, errs := check(t, test.source); strings.Join(errs, "\n") != strings.Join(test.errors, "\n") {
+			t.Errorf("%s:\ngot  %q\nwant %q", test.source, errs, test.errors)
+		}
+	}
+}
+
+func TestDeclarations(t *testing.T) {
+	tests := []struct {
+		source string
+		types  []string
+	}{
+		{"var x = 1; var y = x / 2.0; var s = \"a\" + \"b\";", []string{"x: Int", "y: Float", "s: String"}},
+		{"var xs = [1, 2.5]; var m = {\"a\": [1]};", []string{"xs: List[Float]", "m: Map[String, List[Int]]"}},
+		{"fn f(n: Int) { if (n > 0) { return n; } return 1.5; }", []string{"f: fn(Int) -> Float"}},
+		{"fn f() {}", []string{"f: fn() -> Nil"}},
+		{"fn fact(n: Int) -> Int { return n < 2 ? 1 : n * fact(n - 1); } var v = fact(5);", []string{"fact: fn(Int) -> Int", "v: Int"}},
+		{"fn map[T, U](xs: List[T], f: fn(T) -> U) -> List[U] { return []; } var s = map([1], (x) => \"${x}\");", []string{"map: fn[T, U](List[T], fn(T) -> U) -> List[U]", "s: List[String]"}},
+		{"struct Box[T] { value: T fn get() -> T { return self.value; } } var v = Box(1).get(); var k = keys({\"a\": 1});", []string{"Box.get: fn() -> T", "v: Int", "k: List[String]"}},
+		{"const N = 1; let s = \"a\";", []string{"N: Int", "s: String"}},
+		{"var n = 3; var a = 2 ** 3; var b = 2 ** n; var c = 2 ** -1; var d = 2.0 ** n;", []string{"n: Int", "a: Int", "b: Any", "c: Float", "d: Float"}},
+		{"fn f[T: Display + Eq, U](x: T, y: U) {}", []string{"f: fn[T: Display + Eq, U](T, U) -> Nil"}},
+		{"var i = 0; i += 0.5; var xs = [1]; xs = [nil];", []string{"i: Float", "xs: List[Int]"}},
+	}
+	for _, test := range tests {
+		declarations, errs := check(t, test.source)
+		if errs != nil {
+			t.Errorf("%s: %q", test.source, errs)
+			continue
+		}
+		var types []string
+		for _, v := range declarations {
+			types = append(types, v.Name+": "+v.Type.String())
+		}
+		if strings.Join(types, "\n") != strings.Join(test.types, "\n") {
+			t.Errorf("%s:\ngot  %q\nwant %q", test.source, types, test.types)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package types
+
+import "strings"
+
+// Type is the static type of a value as seen by the checker, Any turns checking off for the value
+type Type interface {
+	String() string
+}
+
+type Basic struct {
+	name string
+}
+
+var (
+	Any    = Basic{name: "Any"}
+	Int    = Basic{name: "Int"}
+	Float  = Basic{name: "Float"}
+	String = Basic{name: "String"}
+	Bool   = Basic{name: "Bool"}
+	Nil    = Basic{name: "Nil"}
+)
+
+type List struct {
+	Elem Type
+}
+
+type Map struct {
+	Key   Type
+	Value Type
+}
+
+// Func is the type of a fn, a generic fn is instantiated with fresh TypeParams at every call
+type Func struct {
+	TypeParams []*TypeParam
+	Params     []Type
+	Return     Type
+}
+
+// TypeParam stands for the type a generic fn or struct is instantiated with, inside of them
+// nothing is known about its values but the methods of the traits it is bound by
+type TypeParam struct {
+	Name   string
+	Bounds []*Trait
+}
+
+// Struct is the type of instances of a struct, the struct itself is typed as its constructor
+type Struct struct {
+	Name       string
+	TypeParams []*TypeParam
+	Fields     []string
+	FieldTypes map[string]Type
+	Methods    map[string]*Func
+	Traits     []*Trait
+}
+
+// Trait is the type of values of any struct having its methods, declaring the trait gives a struct its defaults
+type Trait struct {
+	Name     string
+	Methods  []string
+	Types    map[string]*Func
+	Defaults []string
+}
+
+// Applied is the type of instances of a generic struct with its type parameters replaced by Args
+type Applied struct {
+	Struct *Struct
+	Args   []Type
+}
+
+// Enum is the type of the variants of an enum
+type Enum struct {
+	Name     string
+	Variants []string
+	Fields   map[string][]string
+	Types    map[string][]Type
+}
+
+// EnumObject is the type of the value an enum declaration binds, its variants are reached with .
+type EnumObject struct {
+	Enum *Enum
+}
+
+func (t Basic) String() string {
+	return t.name
+}
+
+func (t List) String() string {
+	return "List[" + t.Elem.String() + "]"
+}
+
+func (t Map) String() string {
+	return "Map[" + t.Key.String() + ", " + t.Value.String() + "]"
+}
+
+func (t *Func) String() string {
+	var typeParams []string
+	for _, v := range t.TypeParams {
+		var bounds []string
+		for _, trait := range v.Bounds {
+			bounds = append(bounds, trait.Name)
+		}
+		if bounds == nil {
+			typeParams = append(typeParams, v.Name)
+		} else {
+			typeParams = append(typeParams, v.Name+": "+strings.Join(bounds, " + "))
+		}
+	}
+	var params []string
+	for _, v := range t.Params {
+		params = append(params, v.String())
+	}
+	result := "fn"
+	if typeParams != nil {
+		result += "[" + strings.Join(typeParams, ", ") + "]"
+	}
+	return result + "(" + strings.Join(params, ", ") + ") -> " + t.Return.String()
+}
+
+func (t *TypeParam) String() string {
+	return t.Name
+}
+
+func (t *Struct) String() string {
+	return t.Name
+}
+
+func (t Applied) String() string {
+	var args []string
+	for _, v := range t.Args {
+		args = append(args, v.String())
+	}
+	return t.Struct.Name + "[" + strings.Join(args, ", ") + "]"
+}
+
+func (t *Trait) String() string {
+	return t.Name
+}
+
+func (t *Enum) String() string {
+	return t.Name
+}
+
+func (t EnumObject) String() string {
+	return "<enum " + t.Enum.Name + ">"
+}
+
+func isNumber(t Type) bool {
+	return t == Int || t == Float
+}
+
+// assignable reports whether a value of type from can be stored where to is expected, nil can be stored anywhere
+func assignable(to Type, from Type) bool {
+	if to == Any || from == Any || from == Nil {
+		return true
+	}
+	switch to := to.(type) {
+	case Basic:
+		return to == from || to == Float && from == Int
+	case List:
+		from, ok := from.(List)
+		return ok && assignable(to.Elem, from.Elem)
+	case Map:
+		from, ok := from.(Map)
+		return ok && assignable(to.Key, from.Key) && assignable(to.Value, from.Value)
+	case *Func:
+		from, ok := from.(*Func)
+		if !ok || len(to.Params) != len(from.Params) {
+			return false
+		}
+		for i, v := range to.Params {
+			if !assignable(from.Params[i], v) {
+				return false
+			}
+		}
+		return assignable(to.Return, from.Return)
+	case Applied:
+		from, ok := from.(Applied)
+		if !ok || to.Struct != from.Struct {
+			return false
+		}
+		for i, v := range to.Args {
+			if !assignable(v, from.Args[i]) || !assignable(from.Args[i], v) {
+				return false
+			}
+		}
+		return true
+	case *Struct:
+		return to == from
+	case *Trait:
+		return conforms(from, to) == ""
+	case *Enum:
+		return to == from
+	case *TypeParam:
+		return to == from
+	}
+	return false
+}
+
+// substitute replaces the type parameters in t that subst has a type for
+func substitute(t Type, subst map[*TypeParam]Type) Type {
+	switch t := t.(type) {
+	case *TypeParam:
+		if val, ok := subst[t]; ok {
+			return val
+		}
+	case List:
+		return List{Elem: substitute(t.Elem, subst)}
+	case Map:
+		return Map{Key: substitute(t.Key, subst), Value: substitute(t.Value, subst)}
+	case *Func:
+		fn := &Func{TypeParams: t.TypeParams, Return: substitute(t.Return, subst)}
+		for _, v := range t.Params {
+			fn.Params = append(fn.Params, substitute(v, subst))
+		}
+		return fn
+	case Applied:
+		applied := Applied{Struct: t.Struct}
+		for _, v := range t.Args {
+			applied.Args = append(applied.Args, substitute(v, subst))
+		}
+		return applied
+	}
+	return t
+}
+
+// unify binds the unbound type parameters of param so that it matches arg
+func unify(param Type, arg Type, subst map[*TypeParam]Type) {
+	switch param := param.(type) {
+	case *TypeParam:
+		if _, ok := subst[param]; !ok && arg != Nil {
+			subst[param] = arg
+		}
+	case List:
+		if arg, ok := arg.(List); ok {
+			unify(param.Elem, arg.Elem, subst)
+		}
+	case Map:
+		if arg, ok := arg.(Map); ok {
+			unify(param.Key, arg.Key, subst)
+			unify(param.Value, arg.Value, subst)
+		}
+	case *Func:
+		if arg, ok := arg.(*Func); ok && len(arg.Params) == len(param.Params) {
+			for i, v := range param.Params {
+				unify(v, arg.Params[i], subst)
+			}
+			unify(param.Return, arg.Return, subst)
+		}
+	case Applied:
+		if arg, ok := arg.(Applied); ok && arg.Struct == param.Struct {
+			for i, v := range param.Args {
+				unify(v, arg.Args[i], subst)
+			}
+		}
+	}
+}
+
+// structOf returns the struct of an instance type and what its type parameters stand for
+func structOf(t Type) (*Struct, map[*TypeParam]Type, bool) {
+	switch t := t.(type) {
+	case *Struct:
+		return t, nil, true
+	case Applied:
+		subst := make(map[*TypeParam]Type)
+		for i, v := range t.Struct.TypeParams {
+			subst[v] = t.Args[i]
+		}
+		return t.Struct, subst, true
+	}
+	return nil, nil, false
+}
+
+// methodOf returns the type of a method of the values of type t
+func methodOf(t Type, name string) (*Func, bool) {
+	if structType, subst, ok := structOf(t); ok {
+		if method, ok := structType.Methods[name]; ok {
+			return substitute(method, subst).(*Func), true
+		}
+		return nil, false
+	}
+	if trait, ok := t.(*Trait); ok {
+		method, ok := trait.Types[name]
+		return method, ok
+	}
+	if param, ok := t.(*TypeParam); ok {
+		for _, trait := range param.Bounds {
+			if method, ok := trait.Types[name]; ok {
+				return method, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// conforms returns why values of type t are not usable as trait, or "" when they are
+func conforms(t Type, trait *Trait) string {
+	for _, name := range trait.Methods {
+		method, ok := methodOf(t, name)
+		if !ok {
+			return "missing method " + name
+		}
+		if !assignable(trait.Types[name], method) {
+			return "method " + name + " is " + method.String() + " but " + trait.Name + " needs " + trait.Types[name].String()
+		}
+	}
+	return ""
+}
+
+// join is the type of a value that is either a or b
+func join(a Type, b Type) Type {
+	if a == Any || b == Any {
+		return Any
+	}
+	if a == Nil {
+		return b
+	}
+	if b == Nil {
+		return a
+	}
+	if isNumber(a) && isNumber(b) {
+		if a == b {
+			return a
+		}
+		return Float
+	}
+	switch a := a.(type) {
+	case List:
+		if b, ok := b.(List); ok {
+			return List{Elem: join(a.Elem, b.Elem)}
+		}
+	case Map:
+		if b, ok := b.(Map); ok {
+			return Map{Key: join(a.Key, b.Key), Value: join(a.Value, b.Value)}
+		}
+	}
+	if assignable(a, b) && assignable(b, a) {
+		return a
+	}
+	return Any
+}